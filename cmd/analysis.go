@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/analyser"
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// analysisRun holds the clients and results of one analysis pass
+type analysisRun struct {
+	Config       awsclient.Config
+	EC2          awsclient.EC2Client
+	CloudWatch   awsclient.CloudWatchClient
+	CostExplorer awsclient.CostExplorerClient
+	Instances    []model.EC2Instance
+	Recs         []model.Recommendation
+}
+
+// connect resolves the mode, prints the mode banner, creates AWS clients and
+// lists instances. It reports any failure itself; ok is false when the caller
+// should stop.
+func connect(ctx context.Context) (*analysisRun, bool) {
+	// Resolve mock vs real mode
+	useMockMode := resolveMockMode(ctx)
+
+	// Display mode
+	fmt.Fprintln(os.Stderr, "MODE:", map[bool]string{true: "Mock", false: "Real AWS"}[useMockMode])
+	fmt.Fprintln(os.Stderr)
+
+	// Create AWS clients
+	clientCfg := awsclient.Config{
+		UseMock: useMockMode,
+		Profile: awsProfile,
+	}
+
+	ec2Client, err := awsclient.New(ctx, clientCfg)
+	if err != nil {
+		logging.DebugErr("EC2 client creation failed", err)
+		return nil, false
+	}
+
+	cwClient, err := awsclient.NewCloudWatch(ctx, clientCfg)
+	if err != nil {
+		logging.DebugErr("CloudWatch client creation failed", err)
+		return nil, false
+	}
+
+	ceClient, err := awsclient.NewCostExplorer(ctx, clientCfg)
+	if err != nil {
+		logging.DebugErr("Cost Explorer client creation failed", err)
+		return nil, false
+	}
+
+	// Fetch EC2 instances
+	instances, err := ec2Client.ListInstances(ctx)
+	if err != nil {
+		logging.DebugErr("EC2 ListInstances failed", err)
+		return nil, false
+	}
+
+	if len(instances) == 0 {
+		fmt.Println("No EC2 instances found.")
+		return nil, false
+	}
+
+	return &analysisRun{
+		Config:       clientCfg,
+		EC2:          ec2Client,
+		CloudWatch:   cwClient,
+		CostExplorer: ceClient,
+		Instances:    instances,
+	}, true
+}
+
+// runAnalysis connects and runs the analyser over every instance.
+// ok is false when the caller should stop.
+func runAnalysis(ctx context.Context) (*analysisRun, bool) {
+	run, ok := connect(ctx)
+	if !ok {
+		return nil, false
+	}
+
+	// Run analysis
+	recs, err := analyser.AnalyseInstances(
+		ctx,
+		run.Instances,
+		run.CloudWatch,
+		run.CostExplorer,
+		analysisOptions(),
+	)
+	if err != nil {
+		fmt.Printf("Analysis failed: %v\n", err)
+		logging.DebugErr("AnalyseInstances failed", err)
+		return nil, false
+	}
+
+	run.Recs = recs
+	return run, true
+}
+
+// analysisOptions collects the analysis flags shared by the commands
+func analysisOptions() analyser.Options {
+	return analyser.Options{
+		MetricHours: metricHours,
+		CostDays:    costDays,
+		IdleDays:    idleDays,
+		StoppedDays: stoppedDays,
+		HeadroomPct: headroomPct,
+		TargetCPU:   targetCPU,
+		PatternDays: patternDays,
+		MinCoverage: minCoverage,
+	}
+}
@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/apply"
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+)
+
+var (
+	applyType string
+	applyYes  bool
+)
+
+var applyCmd = &cobra.Command{
+	Use:   "apply <instance-id>",
+	Short: "Change an instance type, recording it in the change journal",
+	Long: `The apply command changes an instance to the type given by --to using
+the stop/modify/start sequence. Each step is written to the change journal
+before it runs, so the change can be traced and reverted with rollback.
+
+A running instance is stopped for the change and started again afterwards.
+Nothing is changed unless --yes is given.`,
+	Example: `  # Preview, then perform, a resize
+  cloud-optimiser apply i-1234567890abcdef0 --to t3.nano
+  cloud-optimiser apply i-1234567890abcdef0 --to t3.nano --yes`,
+	Args: cobra.ExactArgs(1),
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if _, ok := catalog.Default().Lookup(applyType); !ok {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("unknown instance type %q for --to", applyType)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+		instanceID := args[0]
+
+		j, ok := openJournal()
+		if !ok {
+			return
+		}
+
+		// Resolve mock vs real mode
+		useMockMode := resolveMockMode(ctx)
+
+		// Display mode
+		fmt.Fprintln(os.Stderr, "MODE:", map[bool]string{true: "Mock", false: "Real AWS"}[useMockMode])
+		fmt.Fprintln(os.Stderr)
+
+		client, err := awsclient.New(ctx, awsclient.Config{
+			UseMock: useMockMode,
+			Profile: awsProfile,
+		})
+		if err != nil {
+			fmt.Println("Failed to create EC2 client")
+			logging.DebugErr("EC2 client creation failed", err)
+			return
+		}
+
+		instances, err := client.ListInstances(ctx)
+		if err != nil {
+			fmt.Println("Failed to list instances")
+			logging.DebugErr("EC2 ListInstances failed", err)
+			return
+		}
+
+		found := false
+		for _, inst := range instances {
+			if inst.ID != instanceID {
+				continue
+			}
+			found = true
+
+			if inst.InstanceType == applyType {
+				fmt.Printf("%s is already %s; nothing to change.\n", inst.ID, applyType)
+				break
+			}
+
+			fmt.Printf("Resize %s: %s -> %s\n", inst.ID, inst.InstanceType, applyType)
+			if inst.State == "running" {
+				fmt.Println("The instance will be stopped, modified and started again.")
+			}
+
+			if !applyYes {
+				fmt.Println("\nRe-run with --yes to perform the resize.")
+				break
+			}
+
+			c, err := apply.Resize(ctx, client, j, inst, applyType, operatorName())
+			if err != nil {
+				fmt.Printf("Resize failed: %v\n", err)
+				if c.ID != "" {
+					fmt.Printf("Journal entry: %s [%s] in %s\n", c.ID, c.State, j.Path())
+				}
+				if c.Modified() {
+					fmt.Printf("The type was changed before the failure; revert with: cloud-optimiser rollback %s\n", c.ID)
+				}
+				return
+			}
+
+			fmt.Printf("Resize complete. Journal entry: %s in %s (revert with: cloud-optimiser rollback %s)\n", c.ID, j.Path(), c.ID)
+		}
+
+		if !found {
+			fmt.Printf("Instance %s not found\n", instanceID)
+		}
+
+		if client.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(applyCmd)
+
+	applyCmd.Flags().StringVar(&applyType, "to", "", "Instance type to change to")
+	applyCmd.Flags().BoolVar(&applyYes, "yes", false, "Perform the resize (otherwise only preview it)")
+	applyCmd.Flags().StringVar(&changeOperator, "operator", "", "Operator name recorded in the journal (default: current user)")
+	applyCmd.Flags().StringVar(&journalPath, "journal", "", "Path to the change journal (default: ~/cloud-optimiser/journal.json)")
+	applyCmd.MarkFlagRequired("to")
+}
@@ -0,0 +1,380 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/commitments"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+var (
+	commitmentsOutput string
+	expiryDays        int
+)
+
+var commitmentsCmd = &cobra.Command{
+	Use:   "commitments",
+	Short: "Analyse Reserved Instance and Savings Plans commitments",
+}
+
+var coverageCmd = &cobra.Command{
+	Use:   "coverage",
+	Short: "Show commitment coverage and unused commitment hours per family",
+	Long: `The coverage command loads active Reserved Instances and Savings Plans,
+matches them to running instances, and reports per instance family how many
+commitment hours go unused each month and how much usage is covered.
+
+Utilisation and coverage come from Cost Explorer when it reports them and
+are estimated from the instance inventory otherwise (see SOURCE).`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if commitmentsOutput != "table" && commitmentsOutput != "json" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid output %q (valid: table, json)", commitmentsOutput)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		run, ok := connect(ctx)
+		if !ok {
+			return
+		}
+
+		res, ok := loadCommitments(ctx, run)
+		if !ok {
+			return
+		}
+
+		type coveredInstance struct {
+			InstanceID   string `json:"instance_id"`
+			InstanceType string `json:"instance_type"`
+			model.CommitmentCover
+		}
+		var covered []coveredInstance
+		for _, inst := range run.Instances {
+			if c, ok := res.Cover(inst.ID); ok {
+				covered = append(covered, coveredInstance{inst.ID, inst.InstanceType, c})
+			}
+		}
+
+		if commitmentsOutput == "json" {
+			b, err := json.MarshalIndent(map[string]any{
+				"families":  res.Families,
+				"instances": covered,
+			}, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		} else if len(res.Families) == 0 {
+			fmt.Println("No active Reserved Instances or Savings Plans found.")
+		} else {
+			fmt.Println("Commitments by family:")
+			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "FAMILY\tRIs\tRI HRS/mo\tUNUSED\tRI UTIL\tSPs\tSP $/h\tUNUSED HRS/mo\tSP UTIL\tRI COVER\tSP COVER\tSOURCE")
+			for _, f := range res.Families {
+				fmt.Fprintf(w, "%s\t%d\t%.0f\t%.0f\t%s\t%d\t%s\t%.0f\t%s\t%.0f%%\t%.0f%%\t%s\n",
+					f.Family, f.ReservedInstances, f.RIHours, f.UnusedRIHours, pctOrDash(f.RIUtilisationPct, f.ReservedInstances),
+					f.SavingsPlans, dollarsOrDash(f.SPCommitment), f.UnusedSPHours, pctOrDash(f.SPUtilisationPct, f.SavingsPlans),
+					f.RICoveragePct, f.SPCoveragePct, f.Source)
+			}
+			w.Flush()
+
+			fmt.Println("\nCovered instances:")
+			w = tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "INSTANCE\tTYPE\tCOVERED\tRIs\tSAVINGS PLANS")
+			for _, c := range covered {
+				fmt.Fprintf(w, "%s\t%s\t%.0f%%\t%s\t%s\n", c.InstanceID, c.InstanceType, c.CoveredPct,
+					orDash(strings.Join(c.RIs, ",")), orDash(strings.Join(c.SavingsPlans, ",")))
+			}
+			w.Flush()
+		}
+
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+var purchaseCmd = &cobra.Command{
+	Use:   "recommend",
+	Short: "Propose Savings Plan commitments from steady-state on-demand usage",
+	Long: `The recommend command sizes Savings Plan purchases for the usage that is
+left once right-sizing is done.
+
+The steady-state baseline per instance family is the 10th percentile of
+daily on-demand EC2 cost over --cost-days, adjusted by the changes the
+recommend command would make (commitments already in place are taken into
+account). Compute and EC2 Instance Savings Plans are then proposed for 1 and
+3 year terms, with the hourly commitment, savings and break-even point.`,
+	Example: `  cloud-optimiser commitments recommend
+  cloud-optimiser commitments recommend --cost-days 60 --output json`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if commitmentsOutput != "table" && commitmentsOutput != "json" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid output %q (valid: table, json)", commitmentsOutput)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		run, ok := runAnalysis(ctx)
+		if !ok {
+			return
+		}
+
+		res, ok := loadCommitments(ctx, run)
+		if !ok {
+			return
+		}
+
+		history, err := run.CostExplorer.GetOnDemandCostByFamily(ctx, costDays)
+		if err != nil {
+			fmt.Printf("Failed to load on-demand cost history: %v\n", err)
+			logging.DebugErr("GetOnDemandCostByFamily failed", err)
+			return
+		}
+
+		baselines := commitments.Baselines(history, run.Recs, res)
+		options := commitments.PurchaseOptions(baselines)
+
+		if commitmentsOutput == "json" {
+			b, err := json.MarshalIndent(map[string]any{
+				"baselines": baselines,
+				"options":   options,
+			}, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		} else {
+			printPurchaseOptions(baselines, options)
+		}
+
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+var expiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List Reserved Instances and Savings Plans that expire soon",
+	Long: `The expiring command lists active Reserved Instances and Savings Plans that
+end within --within-days, with the running instances each one covers and the
+estimated monthly on-demand cost increase once it has ended.
+
+The increase is the on-demand price of the covered usage less the
+commitment's own cost (upfront payments are amortised over the term). A
+negative increase means the commitment is mostly unused.`,
+	Example: `  cloud-optimiser commitments expiring
+  cloud-optimiser commitments expiring --within-days 90 --output json`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if commitmentsOutput != "table" && commitmentsOutput != "json" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid output %q (valid: table, json)", commitmentsOutput)
+		}
+		if expiryDays < 0 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("--within-days must not be negative")
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		run, ok := connect(ctx)
+		if !ok {
+			return
+		}
+
+		res, ok := loadCommitments(ctx, run)
+		if !ok {
+			return
+		}
+
+		expiring := res.Expiring(time.Now().UTC(), expiryDays)
+
+		if commitmentsOutput == "json" {
+			b, err := json.MarshalIndent(expiring, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		} else if len(expiring) == 0 {
+			fmt.Printf("No Reserved Instances or Savings Plans expire within %d days.\n", expiryDays)
+		} else {
+			fmt.Printf("Commitments expiring within %d days:\n", expiryDays)
+			w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "ID\tKIND\tCOMMITMENT\tENDS\tDAYS\tCOVERS\tON-DEMAND/mo\tCOST/mo\tINCREASE/mo")
+			total := 0.0
+			for _, e := range expiring {
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t$%.2f\t$%.2f\t%+.2f\n",
+					e.ID, e.Kind, e.Description, e.End.Format("2006-01-02"), e.DaysLeft,
+					orDash(strings.Join(e.Instances, ",")), e.CoveredOnDemand, e.CommitmentCost, e.MonthlyIncrease)
+				total += e.MonthlyIncrease
+			}
+			w.Flush()
+			fmt.Printf("\nEstimated on-demand increase after expiry: %+.2f/mo\n", total)
+		}
+
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(commitmentsCmd)
+	commitmentsCmd.AddCommand(coverageCmd)
+	commitmentsCmd.AddCommand(purchaseCmd)
+	commitmentsCmd.AddCommand(expiringCmd)
+
+	coverageCmd.Flags().StringVar(&commitmentsOutput, "output", "table", "Output format: table | json")
+	coverageCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of Cost Explorer utilisation and coverage to analyze")
+
+	purchaseCmd.Flags().StringVar(&commitmentsOutput, "output", "table", "Output format: table | json")
+	purchaseCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of on-demand cost history used for the baseline")
+	purchaseCmd.Flags().IntVar(&metricHours, "metric-hours", 24, "Hours of CPU metrics to analyze")
+	purchaseCmd.Flags().IntVar(&idleDays, "idle-days", 30, "Days of CPU and network data used to confirm idle instances (0 disables)")
+	purchaseCmd.Flags().IntVar(&stoppedDays, "stopped-days", 30, "Days stopped before suggesting snapshot and terminate (0 disables)")
+	purchaseCmd.Flags().Float64Var(&headroomPct, "headroom", 20, "Percent of capacity to keep above observed CPU/memory peaks when right-sizing")
+	purchaseCmd.Flags().Float64Var(&targetCPU, "target-cpu", 80, "Reject suggestions whose projected p95 CPU exceeds this percent (0 disables)")
+	purchaseCmd.Flags().IntVar(&patternDays, "pattern-days", 0, "Days of hourly CPU searched for daily/weekly cycles and recurring peaks, e.g. 62 to see two month ends (0 disables)")
+	purchaseCmd.Flags().Float64Var(&minCoverage, "min-coverage", 50, "Hold actions for review when fewer than this percent of expected CPU datapoints arrived (0 disables)")
+
+	expiringCmd.Flags().StringVar(&commitmentsOutput, "output", "table", "Output format: table | json")
+	expiringCmd.Flags().IntVar(&expiryDays, "within-days", 60, "List commitments ending within this many days")
+	expiringCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of Cost Explorer utilisation and coverage to analyze")
+}
+
+// printPurchaseOptions prints the baseline per family and the proposed plans
+func printPurchaseOptions(baselines []model.FamilyBaseline, options []model.SavingsPlanOption) {
+	fmt.Println("Steady-state on-demand baseline:")
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "FAMILY\tHISTORICAL $/h\tRIGHT-SIZING $/h\tBASELINE $/h")
+	var hist, change, total float64
+	for _, b := range baselines {
+		fmt.Fprintf(w, "%s\t%.4f\t%+.4f\t%.4f\n", b.Family, b.Historical, b.RightSizing, b.BaselineRate)
+		hist += b.Historical
+		change += b.RightSizing
+		total += b.BaselineRate
+	}
+	fmt.Fprintf(w, "TOTAL\t%.4f\t%+.4f\t%.4f\n", hist, change, total)
+	w.Flush()
+
+	if len(options) == 0 {
+		fmt.Println("\nNo steady-state on-demand usage left to commit to.")
+		return
+	}
+
+	fmt.Println("\nSavings Plan options (no upfront), one alternative per letter:")
+	w = tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "OPTION\tPLAN\tFAMILY\tTERM\tDISCOUNT\tCOMMIT $/h\tCOMMIT/mo\tSAVING/mo\tSAVING/term\tBREAK-EVEN")
+	row := func(letter string, o model.SavingsPlanOption) {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%dy\t%.0f%%\t$%.4f\t$%.2f\t$%.2f\t$%.2f\tmonth %.0f or %.0f%% of baseline\n",
+			letter, o.Type, orDash(o.Family), o.TermYears, o.DiscountPct, o.HourlyCommitment, o.MonthlyCommitment,
+			o.MonthlySaving, o.TermSaving, math.Ceil(o.BreakEvenMonth), o.BreakEvenUsagePct)
+	}
+	// Options are grouped by alternative; per-family plans are totalled
+	for start, n := 0, 0; start < len(options); n++ {
+		end := start + 1
+		for end < len(options) && options[end].Alternative == options[start].Alternative {
+			end++
+		}
+		letter := string(rune('A' + n))
+		total := options[start]
+		total.Family = "total"
+		total.HourlyCommitment, total.MonthlyCommitment, total.MonthlySaving, total.TermSaving = 0, 0, 0, 0
+		for _, o := range options[start:end] {
+			row(letter, o)
+			total.HourlyCommitment += o.HourlyCommitment
+			total.MonthlyCommitment += o.MonthlyCommitment
+			total.MonthlySaving += o.MonthlySaving
+			total.TermSaving += o.TermSaving
+		}
+		if end-start > 1 {
+			row(letter, total)
+		}
+		start = end
+	}
+	w.Flush()
+
+	fmt.Println("\nThe lettered options cover the same usage: buy one of them, as their savings do not add up. Per-family plans within one option are bought together.")
+	fmt.Println("Break-even: the covered usage must last until that month of the term, or never drop below that share of the baseline, for the plan to beat on-demand.")
+}
+
+// loadCommitments lists active RIs and Savings Plans, fetches Cost Explorer
+// utilisation and coverage (best effort) and matches them to the run's instances
+func loadCommitments(ctx context.Context, run *analysisRun) (*commitments.Result, bool) {
+	client, err := awsclient.NewCommitments(ctx, run.Config)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to create commitments client: %v\n", err)
+		logging.DebugErr("Commitments client creation failed", err)
+		return nil, false
+	}
+
+	ris, err := client.ListReservedInstances(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list Reserved Instances: %v\n", err)
+		logging.DebugErr("ListReservedInstances failed", err)
+		return nil, false
+	}
+
+	plans, err := client.ListSavingsPlans(ctx)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Failed to list Savings Plans: %v\n", err)
+		logging.DebugErr("ListSavingsPlans failed", err)
+		return nil, false
+	}
+
+	// Without Cost Explorer data, utilisation and coverage are estimated
+	util, err := run.CostExplorer.GetCommitmentUtilisation(ctx, costDays)
+	if err != nil {
+		logging.DebugErr("Commitment utilisation unavailable", err)
+	}
+	coverage, err := run.CostExplorer.GetCommitmentCoverage(ctx, costDays)
+	if err != nil {
+		logging.DebugErr("Commitment coverage unavailable", err)
+	}
+
+	return commitments.Analyse(commitments.Input{
+		Instances:         run.Instances,
+		ReservedInstances: ris,
+		SavingsPlans:      plans,
+		Utilisation:       util,
+		Coverage:          coverage,
+	}, time.Now().UTC()), true
+}
+
+// pctOrDash formats a percentage, or "-" when there is nothing to measure
+func pctOrDash(pct float64, n int) string {
+	if n == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("%.0f%%", pct)
+}
+
+// dollarsOrDash formats an hourly amount, or "-" when it is zero
+func dollarsOrDash(v float64) string {
+	if v == 0 {
+		return "-"
+	}
+	return fmt.Sprintf("$%.3f", v)
+}
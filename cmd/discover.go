@@ -2,44 +2,59 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
 
 	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
 	"github.com/PanaAnt/cloud-optimiser/internal/config"
 	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+	"github.com/PanaAnt/cloud-optimiser/internal/policy"
 	"github.com/spf13/cobra"
 )
 
+var (
+	checkTags     bool
+	tagPolicyPath string
+	maxViolations int
+
+	discoverWide   bool
+	discoverOutput string
+)
+
 var discoverCmd = &cobra.Command{
 	Use:   "discover",
 	Short: "Discover EC2 instances (mock or real AWS)",
 	Long: `The discover command lists EC2 instances using either:
   • Mock data (default, safe)
-  • Real AWS API (when enabled via config or flag)`,
-	Run: func(cmd *cobra.Command, args []string) {
-		ctx := context.Background()
+  • Real AWS API (when enabled via config or flag)
 
-		// Load config & resolve initial mode
-		cfg, err := config.LoadConfig()
-		if err != nil {
-			logging.Warn(fmt.Sprintf("Could not load config: %v, defaulting to MOCK mode", err))
-			cfg = config.AppConfig{Mode: "mock"}
-		}
-
-		useMockMode := useMock || cfg.Mode == "mock"
+Use --wide for placement, platform, lifecycle, networking and storage
+details, or --output json for the full instance records.
 
-		// AWS readiness check (only if not already in mock mode)
-		if !useMockMode {
-			if err := awsclient.CanUseRealAWS(ctx, awsProfile); err != nil {
-				fmt.Println("AWS unavailable – switching to MOCK mode.")
-				logging.DebugErr("AWS readiness check failed", err)
-				useMockMode = true
-			}
+With --check-tags, every instance is evaluated against a tag policy
+(required keys, allowed values and regex patterns). The command exits
+non-zero when violations exceed --max-violations.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if discoverOutput != "table" && discoverOutput != "json" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid output %q (valid: table, json)", discoverOutput)
 		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		// Resolve mock vs real mode
+		useMockMode := resolveMockMode(ctx)
 
 		// Display mode
-		fmt.Println("MODE:", map[bool]string{true: "Mock", false: "Real AWS"}[useMockMode])
-		fmt.Println()
+		fmt.Fprintln(os.Stderr, "MODE:", map[bool]string{true: "Mock", false: "Real AWS"}[useMockMode])
+		fmt.Fprintln(os.Stderr)
 
 		// Create EC2 client
 		client, err := awsclient.New(ctx, awsclient.Config{
@@ -66,17 +81,124 @@ var discoverCmd = &cobra.Command{
 		}
 
 		// Output
-		fmt.Println("Discovered EC2 Instances:")
-		for _, inst := range instances {
-			fmt.Printf(" - %s (%s) [%s]\n", inst.ID, inst.InstanceType, inst.State)
+		switch {
+		case discoverOutput == "json":
+			b, err := json.MarshalIndent(instances, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		case discoverWide:
+			printInstancesWide(instances)
+		default:
+			fmt.Println("Discovered EC2 Instances:")
+			for _, inst := range instances {
+				fmt.Printf(" - %s (%s) [%s]\n", inst.ID, inst.InstanceType, inst.State)
+			}
 		}
-		
+
 		if client.IsMock() {
-			fmt.Println("\n[Note: Using mock data]")
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+
+		if checkTags && !checkTagCompliance(instances) {
+			os.Exit(1)
 		}
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(discoverCmd)
+
+	discoverCmd.Flags().BoolVar(&discoverWide, "wide", false, "Show placement, platform, lifecycle, networking and storage columns")
+	discoverCmd.Flags().StringVar(&discoverOutput, "output", "table", "Output format: table | json")
+	discoverCmd.Flags().BoolVar(&checkTags, "check-tags", false, "Evaluate instances against the tag policy")
+	discoverCmd.Flags().StringVar(&tagPolicyPath, "tag-policy", "", "Path to the tag policy (default: ~/cloud-optimiser/tag_policy.json)")
+	discoverCmd.Flags().IntVar(&maxViolations, "max-violations", 0, "Exit non-zero when tag violations exceed this number")
+}
+
+// printInstancesWide prints one row per instance with the enriched fields
+func printInstancesWide(instances []model.EC2Instance) {
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tSTATE\tAZ\tPLATFORM\tARCH\tTENANCY\tLIFECYCLE\tASG\tVPC\tSUBNET\tVOLUMES\tLAUNCHED\tIAM PROFILE")
+	for _, inst := range instances {
+		var sizeGiB int32
+		for _, v := range inst.Volumes {
+			sizeGiB += v.SizeGiB
+		}
+
+		launched := "-"
+		if !inst.LaunchTime.IsZero() {
+			launched = inst.LaunchTime.Format("2006-01-02")
+		}
+
+		profile := inst.IAMInstanceProfile
+		if i := strings.LastIndex(profile, "/"); i >= 0 {
+			profile = profile[i+1:]
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%d (%d GiB)\t%s\t%s\n",
+			inst.ID, inst.InstanceType, inst.State,
+			orDash(inst.AvailabilityZone), orDash(inst.Platform), orDash(inst.Architecture),
+			orDash(inst.Tenancy), orDash(inst.Lifecycle), orDash(inst.AutoScalingGroup),
+			orDash(inst.VpcID), orDash(inst.SubnetID),
+			len(inst.Volumes), sizeGiB, launched, orDash(profile))
+	}
+	w.Flush()
+}
+
+// checkTagCompliance prints policy violations and compliance per account/region.
+// It returns false when violations exceed the allowed threshold.
+func checkTagCompliance(instances []model.EC2Instance) bool {
+	path := tagPolicyPath
+	if path == "" {
+		dir, err := config.Dir()
+		if err != nil {
+			fmt.Printf("Could not locate tag policy: %v\n", err)
+			return false
+		}
+		path = filepath.Join(dir, "tag_policy.json")
+	}
+
+	p, err := policy.LoadTagPolicy(path)
+	if err != nil {
+		fmt.Printf("Could not load tag policy: %v\n", err)
+		return false
+	}
+
+	violations, compliance := p.Check(instances)
+
+	fmt.Println("\nTag Compliance:")
+	if len(violations) == 0 {
+		fmt.Println("No tag policy violations found.")
+	} else {
+		w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+		fmt.Fprintln(w, "INSTANCE\tTAG\tRULE\tDETAIL")
+		for _, v := range violations {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", v.InstanceID, v.Key, v.Rule, v.Message)
+		}
+		w.Flush()
+	}
+
+	fmt.Println()
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ACCOUNT\tREGION\tINSTANCES\tCOMPLIANT\tCOMPLIANCE")
+	for _, c := range compliance {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%d\t%.0f%%\n", orDash(c.AccountID), orDash(c.Region), c.Instances, c.Compliant, c.Percent)
+	}
+	w.Flush()
+
+	if len(violations) > maxViolations {
+		fmt.Printf("\n%d violation(s) exceed the allowed maximum of %d\n", len(violations), maxViolations)
+		return false
+	}
+	return true
+}
+
+func orDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
 }
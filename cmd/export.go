@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/export"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+var (
+	reportPath  string
+	tfDir       string
+	tfStatePath string
+	tfWrite     bool
+	patchFile   string
+
+	cfnTemplateDir string
+	cfnFormat      string
+	cfnOutFile     string
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Turn recommendations into infrastructure-as-code changes",
+	Long: `The export commands map a saved recommendation report onto
+infrastructure-as-code so changes go through review instead of
+being applied directly to AWS.`,
+}
+
+var exportTerraformCmd = &cobra.Command{
+	Use:   "terraform",
+	Short: "Generate instance_type changes for Terraform code",
+	Long: `Maps recommendations to aws_instance and aws_launch_template resources
+using Terraform state (by instance ID, launch template tag or Name tag),
+then prints a patch for the matching .tf files, or rewrites them with --write.`,
+	Example: `  cloud-optimiser recommend --output json > report.json
+  cloud-optimiser export terraform --report report.json --tf-dir ./infra
+  cloud-optimiser export terraform --report report.json --tf-dir ./infra --state state.json --write`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		recs, err := export.LoadReport(reportPath)
+		if err != nil {
+			fmt.Printf("Could not load report: %v\n", err)
+			return
+		}
+
+		statePath := tfStatePath
+		if statePath == "" {
+			statePath = filepath.Join(tfDir, "terraform.tfstate")
+		}
+
+		resources, err := export.LoadTerraformState(statePath)
+		if err != nil {
+			fmt.Printf("Could not load Terraform state: %v\n", err)
+			return
+		}
+
+		instances, ok := instancesByID(ctx)
+		if !ok {
+			return
+		}
+
+		plan, err := export.PlanTerraform(recs, instances, resources, tfDir)
+		if err != nil {
+			fmt.Printf("Failed to map recommendations: %v\n", err)
+			return
+		}
+
+		if len(plan.Edits) == 0 {
+			fmt.Println("No Terraform changes to make.")
+		} else if tfWrite {
+			if err := plan.Apply(); err != nil {
+				fmt.Printf("Failed to rewrite Terraform files: %v\n", err)
+				return
+			}
+			fmt.Printf("Updated %d resource(s):\n", len(plan.Edits))
+			for _, e := range plan.Edits {
+				fmt.Printf(" - %s (%s:%d) %s -> %s\n", e.Address, e.File, e.Line, e.OldType, e.NewType)
+			}
+		} else {
+			patch, err := plan.Patch(tfDir)
+			if err != nil {
+				fmt.Printf("Failed to build patch: %v\n", err)
+				return
+			}
+			if patchFile != "" {
+				if err := os.WriteFile(patchFile, []byte(patch), 0644); err != nil {
+					fmt.Printf("Failed to write patch: %v\n", err)
+					return
+				}
+				fmt.Printf("Patch for %d resource(s) written to %s\n", len(plan.Edits), patchFile)
+			} else {
+				fmt.Print(patch)
+			}
+		}
+
+		printUnmapped(plan.Unmapped)
+	},
+}
+
+var exportCloudFormationCmd = &cobra.Command{
+	Use:     "cloudformation",
+	Aliases: []string{"cfn"},
+	Short:   "Generate per-stack change sets for CloudFormation and CDK stacks",
+	Long: `Maps recommendations to stack resources using the aws:cloudformation:stack-name
+and aws:cloudformation:logical-id tags, and outputs parameter overrides or
+template property changes per stack for review. CloudFormation is never called.
+
+With --template-dir, synthesized JSON templates (e.g. cdk.out) are used to
+detect parameter-driven instance types and follow Auto Scaling groups to
+their launch templates.`,
+	Example: `  cloud-optimiser export cloudformation --report report.json
+  cloud-optimiser export cfn --report report.json --template-dir cdk.out --format yaml`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		if cfnFormat != "json" && cfnFormat != "yaml" {
+			fmt.Println("Error: Invalid format. Use: json or yaml")
+			return
+		}
+
+		recs, err := export.LoadReport(reportPath)
+		if err != nil {
+			fmt.Printf("Could not load report: %v\n", err)
+			return
+		}
+
+		instances, ok := instancesByID(ctx)
+		if !ok {
+			return
+		}
+
+		out, err := export.PlanCloudFormation(recs, instances, cfnTemplateDir)
+		if err != nil {
+			fmt.Printf("Failed to map recommendations: %v\n", err)
+			return
+		}
+
+		var rendered string
+		if cfnFormat == "yaml" {
+			rendered = out.YAML()
+		} else {
+			b, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			rendered = string(b) + "\n"
+		}
+
+		if cfnOutFile != "" {
+			if err := os.WriteFile(cfnOutFile, []byte(rendered), 0644); err != nil {
+				fmt.Printf("Failed to write output: %v\n", err)
+				return
+			}
+			fmt.Printf("Change sets for %d stack(s) written to %s\n", len(out.Stacks), cfnOutFile)
+		} else {
+			fmt.Print(rendered)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+	exportCmd.AddCommand(exportTerraformCmd)
+	exportCmd.AddCommand(exportCloudFormationCmd)
+
+	exportCmd.PersistentFlags().StringVar(&reportPath, "report", "", "Recommendation report from `recommend --output json`")
+	exportCmd.MarkPersistentFlagRequired("report")
+
+	exportTerraformCmd.Flags().StringVar(&tfDir, "tf-dir", ".", "Directory containing Terraform files")
+	exportTerraformCmd.Flags().StringVar(&tfStatePath, "state", "", "Terraform state or `terraform show -json` output (default: <tf-dir>/terraform.tfstate)")
+	exportTerraformCmd.Flags().BoolVar(&tfWrite, "write", false, "Rewrite .tf files in place instead of printing a patch")
+	exportTerraformCmd.Flags().StringVar(&patchFile, "patch-file", "", "Write the patch to a file instead of stdout")
+
+	exportCloudFormationCmd.Flags().StringVar(&cfnTemplateDir, "template-dir", "", "Directory of synthesized JSON templates (<stack>.template.json)")
+	exportCloudFormationCmd.Flags().StringVar(&cfnFormat, "format", "json", "Output format: json | yaml")
+	exportCloudFormationCmd.Flags().StringVar(&cfnOutFile, "out-file", "", "Write output to a file instead of stdout")
+}
+
+// instancesByID lists instances (mock or real) so exporters can match on tags
+func instancesByID(ctx context.Context) (map[string]model.EC2Instance, bool) {
+	client, err := awsclient.New(ctx, awsclient.Config{
+		UseMock: resolveMockMode(ctx),
+		Profile: awsProfile,
+	})
+	if err != nil {
+		fmt.Println("Failed to create EC2 client")
+		logging.DebugErr("EC2 client creation failed", err)
+		return nil, false
+	}
+
+	instances, err := client.ListInstances(ctx)
+	if err != nil {
+		fmt.Println("Failed to list instances")
+		logging.DebugErr("EC2 ListInstances failed", err)
+		return nil, false
+	}
+
+	byID := make(map[string]model.EC2Instance, len(instances))
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+	return byID, true
+}
+
+// printUnmapped lists recommendations that could not be traced to code
+func printUnmapped(unmapped []export.Unmapped) {
+	if len(unmapped) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "\nCould not map %d instance(s) to code:\n", len(unmapped))
+	for _, u := range unmapped {
+		fmt.Fprintf(os.Stderr, " - %s (-> %s): %s\n", u.InstanceID, u.SuggestedType, u.Reason)
+	}
+}
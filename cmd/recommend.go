@@ -2,125 +2,104 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"sort"
-	"text/tabwriter"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/PanaAnt/cloud-optimiser/internal/analyser"
 	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
-	"github.com/PanaAnt/cloud-optimiser/internal/config"
 	"github.com/PanaAnt/cloud-optimiser/internal/logging"
 	"github.com/PanaAnt/cloud-optimiser/internal/model"
+	"github.com/PanaAnt/cloud-optimiser/internal/report"
 )
 
 var (
 	metricHours int
 	costDays    int
-
-	sortBy       string
-	outputFormat string
-	onlyDownsize bool
-	onlyUpsize   bool
-	stateFilter  string
-	minCPU       float64
+	idleDays    int
+	stoppedDays int
+	headroomPct float64
+	targetCPU   float64
+	patternDays int
+	minCoverage float64
+
+	sortBy        string
+	outputFormat  string
+	outFile       string
+	topN          int
+	onlyDownsize  bool
+	onlyUpsize    bool
+	stateFilter   string
+	minCPU        float64
+	minConfidence float64
+
+	spotAdvice bool
+	spotDays   int
+
+	withCommitments bool
 )
 
 var recommendCmd = &cobra.Command{
 	Use:   "recommend",
 	Short: "Analyse EC2 instances and print optimization recommendations",
-	Run: func(cmd *cobra.Command, args []string) {
-		ctx := context.Background()
-
-		// Load config & resolve initial mode
-		cfg, err := config.LoadConfig()
-		if err != nil {
-			logging.Warn(fmt.Sprintf("Could not load config: %v, defaulting to MOCK mode", err))
-			cfg = config.AppConfig{Mode: "mock"}
-		}
-
-		useMockMode := useMock || cfg.Mode == "mock"
-
-		// AWS readiness check (only if not already in mock mode)
-		if !useMockMode {
-			if err := awsclient.CanUseRealAWS(ctx, awsProfile); err != nil {
-				logging.DebugErr("AWS readiness check failed", err)
-				useMockMode = true
-			}
-		}
-
-		// Display mode
-		fmt.Println("MODE:", map[bool]string{true: "Mock", false: "Real AWS"}[useMockMode])
-		fmt.Println()
-
-		// Create AWS clients
-		clientCfg := awsclient.Config{
-			UseMock: useMockMode,
-			Profile: awsProfile,
-		}
-
-		ec2Client, err := awsclient.New(ctx, clientCfg)
-		if err != nil {
-			logging.DebugErr("EC2 client creation failed", err)
-			return
-		}
-
-		cwClient, err := awsclient.NewCloudWatch(ctx, clientCfg)
-		if err != nil {
-			logging.DebugErr("CloudWatch client creation failed", err)
-			return
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		// Reject unknown formats before doing any AWS calls
+		if _, err := report.Get(outputFormat); err != nil {
+			cmd.SilenceUsage = true
+			return err
 		}
-
-		ceClient, err := awsclient.NewCostExplorer(ctx, clientCfg)
-		if err != nil {
-			logging.DebugErr("Cost Explorer client creation failed", err)
-			return
+		if minConfidence < 0 || minConfidence > 1 {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid --min-confidence %.2f (valid: 0 to 1)", minConfidence)
 		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
 
-		// Fetch EC2 instances
-		instances, err := ec2Client.ListInstances(ctx)
-		if err != nil {
-			logging.DebugErr("EC2 ListInstances failed", err)
+		run, ok := runAnalysis(ctx)
+		if !ok {
 			return
 		}
-
-		if len(instances) == 0 {
-			fmt.Println("No EC2 instances found.")
-			return
+		recs := run.Recs
+
+		if spotAdvice {
+			sp, err := awsclient.NewSpotPricing(ctx, run.Config)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Spot advice unavailable: %v\n", err)
+				logging.DebugErr("Spot pricing client creation failed", err)
+			} else {
+				analyser.AdviseSpot(ctx, recs, run.Instances, run.CloudWatch, sp, spotDays)
+			}
 		}
 
-		// Run analysis
-		recs, err := analyser.AnalyseInstances(
-			ctx,
-			instances,
-			cwClient,
-			ceClient,
-			metricHours,
-			costDays,
-		)
-		if err != nil {
-			fmt.Printf("Analysis failed: %v\n", err)
-			logging.DebugErr("AnalyseInstances failed", err)
-			return
+		var expiring []model.CommitmentExpiry
+		if withCommitments {
+			if res, ok := loadCommitments(ctx, run); ok {
+				res.Mark(recs)
+				expiring = res.Expiring(time.Now().UTC(), expiryDays)
+			}
 		}
 
 		// Filter + sort
 		recs = applyFilters(recs)
 		sortRecommendations(recs)
 
-		// 8. Output
-		if outputFormat == "json" {
-			outputJSON(recs)
-		} else {
-			outputTable(recs)
+		// Output
+		rep := report.New(recs, topN)
+		rep.Summary.ExpiringCommitments = expiring
+		if err := writeReport(rep); err != nil {
+			fmt.Printf("Failed to write report: %v\n", err)
+			return
 		}
-		
+
 		// Indicate if using mock data
-		if ec2Client.IsMock() {
-			fmt.Println("\n[Note: Using mock data]")
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
 		}
 	},
 }
@@ -130,12 +109,25 @@ func init() {
 
 	recommendCmd.Flags().IntVar(&metricHours, "metric-hours", 24, "Hours of CPU metrics to analyze")
 	recommendCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of cost data to analyze")
+	recommendCmd.Flags().IntVar(&idleDays, "idle-days", 30, "Days of CPU and network data used to confirm idle instances (0 disables)")
+	recommendCmd.Flags().IntVar(&stoppedDays, "stopped-days", 30, "Days stopped before suggesting snapshot and terminate (0 disables)")
+	recommendCmd.Flags().Float64Var(&headroomPct, "headroom", 20, "Percent of capacity to keep above observed CPU/memory peaks when right-sizing")
+	recommendCmd.Flags().Float64Var(&targetCPU, "target-cpu", 80, "Reject suggestions whose projected p95 CPU exceeds this percent (0 disables)")
+	recommendCmd.Flags().IntVar(&patternDays, "pattern-days", 0, "Days of hourly CPU searched for daily/weekly cycles and recurring peaks, e.g. 62 to see two month ends (0 disables)")
+	recommendCmd.Flags().Float64Var(&minCoverage, "min-coverage", 50, "Hold actions for review when fewer than this percent of expected CPU datapoints arrived (0 disables)")
+	recommendCmd.Flags().BoolVar(&spotAdvice, "spot", false, "Score instances for Spot suitability using spot price history")
+	recommendCmd.Flags().IntVar(&spotDays, "spot-days", 7, "Days of spot price history to analyze")
+	recommendCmd.Flags().BoolVar(&withCommitments, "commitments", false, "Account for Reserved Instances and Savings Plans that already cover instances")
+	recommendCmd.Flags().IntVar(&expiryDays, "expiry-days", 60, "With --commitments, list commitments ending within this many days in the summary")
 	recommendCmd.Flags().StringVar(&sortBy, "sort", "none", "Sort by: cpu | cost | savings")
-	recommendCmd.Flags().StringVar(&outputFormat, "output", "table", "Output format: table | json")
+	recommendCmd.Flags().StringVar(&outputFormat, "output", "table", "Output format: "+strings.Join(report.Formats(), " | "))
+	recommendCmd.Flags().IntVar(&topN, "top", 5, "Number of top savers to list in the summary")
+	recommendCmd.Flags().StringVar(&outFile, "out-file", "", "Write the report to a file instead of stdout")
 	recommendCmd.Flags().BoolVar(&onlyDownsize, "only-downsize", false, "Show only downsize recommendations")
 	recommendCmd.Flags().BoolVar(&onlyUpsize, "only-upsize", false, "Show only upsize recommendations")
 	recommendCmd.Flags().StringVar(&stateFilter, "state", "", "Filter by instance state (e.g., running, stopped)")
 	recommendCmd.Flags().Float64Var(&minCPU, "min-cpu", 0, "Minimum average CPU threshold")
+	recommendCmd.Flags().Float64Var(&minConfidence, "min-confidence", 0, "Only show recommendations with at least this confidence (0-1)")
 }
 
 // applyFilters filters recommendations based on command line flags
@@ -154,6 +146,9 @@ func applyFilters(recs []model.Recommendation) []model.Recommendation {
 		if r.AvgCPU < minCPU {
 			continue
 		}
+		if r.Confidence < minConfidence {
+			continue
+		}
 		out = append(out, r)
 	}
 	return out
@@ -171,35 +166,27 @@ func sortRecommendations(recs []model.Recommendation) {
 	}
 }
 
-// outputJSON outputs recommendations in JSON format
-func outputJSON(recs []model.Recommendation) {
-	b, err := json.MarshalIndent(recs, "", "  ")
+// writeReport renders the report in the selected format to stdout or --out-file
+func writeReport(rep report.Report) error {
+	renderer, err := report.Get(outputFormat)
 	if err != nil {
-		fmt.Printf("Failed to marshal JSON: %v\n", err)
-		return
+		return err
 	}
-	fmt.Println(string(b))
-}
 
-// outputTable outputs recommendations in table format
-func outputTable(recs []model.Recommendation) {
-	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
-	fmt.Fprintln(w, "ID\tTYPE\tSTATE\tCPU(avg)\tCPU(peak)\tCOST/mo\tACTION\tNEW TYPE\tSAVING\tREASON")
-	for _, r := range recs {
-		fmt.Fprintf(
-			w,
-			"%s\t%s\t%s\t%.1f%%\t%.1f%%\t$%.2f\t%s\t%s\t$%.2f\t%s\n",
-			r.InstanceID,
-			r.InstanceType,
-			r.State,
-			r.AvgCPU,
-			r.PeakCPU,
-			r.MonthlyCost,
-			r.Action,
-			r.SuggestedType,
-			r.EstimatedSaving,
-			r.Reason,
-		)
+	if outFile == "" {
+		return renderer.Render(os.Stdout, rep)
 	}
-	w.Flush()
+
+	f, err := os.Create(outFile)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	if err := renderer.Render(f, rep); err != nil {
+		return err
+	}
+
+	fmt.Printf("Report written to %s\n", outFile)
+	return nil
 }
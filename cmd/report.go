@@ -0,0 +1,115 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+	"github.com/PanaAnt/cloud-optimiser/internal/report"
+)
+
+var (
+	chargebackTags      string
+	chargebackReconcile bool
+	chargebackOutput    string
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Aggregate recommendations into finance-oriented reports",
+}
+
+var chargebackCmd = &cobra.Command{
+	Use:   "chargeback",
+	Short: "Allocate spend and savings by tag (e.g. team, environment)",
+	Long: `The chargeback report groups monthly cost and estimated savings by the
+values of one or more instance tags. Instances missing a tag are reported
+in an "untagged" bucket.
+
+With --reconcile, Cost Explorer's TAG group-by is queried for the same keys
+(at most two) so the numbers can be compared with the bill.`,
+	Example: `  cloud-optimiser report chargeback --by-tag Team
+  cloud-optimiser report chargeback --by-tag Team,Environment --reconcile`,
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		var keys []string
+		for _, k := range strings.Split(chargebackTags, ",") {
+			if k = strings.TrimSpace(k); k != "" {
+				keys = append(keys, k)
+			}
+		}
+		if len(keys) == 0 {
+			fmt.Println("Error: --by-tag requires at least one tag key")
+			return
+		}
+		if chargebackReconcile && len(keys) > 2 {
+			fmt.Println("Error: --reconcile supports at most two tag keys")
+			return
+		}
+		if chargebackOutput != "table" && chargebackOutput != "json" {
+			fmt.Println("Error: Invalid output. Use: table or json")
+			return
+		}
+
+		run, ok := runAnalysis(ctx)
+		if !ok {
+			return
+		}
+
+		instances := make(map[string]model.EC2Instance, len(run.Instances))
+		for _, inst := range run.Instances {
+			instances[inst.ID] = inst
+		}
+
+		cb := report.BuildChargeback(run.Recs, instances, keys)
+
+		if chargebackReconcile {
+			costs, err := run.CostExplorer.GetCostByTags(ctx, keys, costDays)
+			if err != nil {
+				fmt.Fprintln(os.Stderr, "Could not load tagged costs from Cost Explorer; showing analysed costs only")
+				logging.DebugErr("GetCostByTags failed", err)
+			} else {
+				cb.Reconcile(costs)
+			}
+		}
+
+		if chargebackOutput == "json" {
+			b, err := json.MarshalIndent(cb, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		} else if err := report.RenderChargebackTable(os.Stdout, cb); err != nil {
+			fmt.Printf("Failed to write report: %v\n", err)
+			return
+		}
+
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+	reportCmd.AddCommand(chargebackCmd)
+
+	chargebackCmd.Flags().StringVar(&chargebackTags, "by-tag", "Team", "Comma-separated tag keys to group by")
+	chargebackCmd.Flags().BoolVar(&chargebackReconcile, "reconcile", false, "Compare with Cost Explorer cost grouped by the same tags")
+	chargebackCmd.Flags().StringVar(&chargebackOutput, "output", "table", "Output format: table | json")
+	chargebackCmd.Flags().IntVar(&metricHours, "metric-hours", 24, "Hours of CPU metrics to analyze")
+	chargebackCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of cost data to analyze")
+	chargebackCmd.Flags().IntVar(&idleDays, "idle-days", 30, "Days of CPU and network data used to confirm idle instances (0 disables)")
+	chargebackCmd.Flags().IntVar(&stoppedDays, "stopped-days", 30, "Days stopped before suggesting snapshot and terminate (0 disables)")
+	chargebackCmd.Flags().Float64Var(&headroomPct, "headroom", 20, "Percent of capacity to keep above observed CPU/memory peaks when right-sizing")
+	chargebackCmd.Flags().Float64Var(&targetCPU, "target-cpu", 80, "Reject suggestions whose projected p95 CPU exceeds this percent (0 disables)")
+	chargebackCmd.Flags().IntVar(&patternDays, "pattern-days", 0, "Days of hourly CPU searched for daily/weekly cycles and recurring peaks, e.g. 62 to see two month ends (0 disables)")
+}
@@ -0,0 +1,198 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/user"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/apply"
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/journal"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+)
+
+var (
+	rollbackYes    bool
+	rollbackList   bool
+	changeOperator string
+	journalPath    string
+)
+
+var rollbackCmd = &cobra.Command{
+	Use:   "rollback [change-id]",
+	Short: "Revert a journaled instance type change",
+	Long: `The rollback command restores the instance type recorded in the change journal,
+using the same stop/modify/start sequence as the original change.
+
+Rollback refuses to run if the instance type no longer matches what the
+change left it on (drift), and only acts when --yes is given.`,
+	Example: `  # List journaled changes
+  cloud-optimiser rollback --list
+
+  # Preview and then revert a change
+  cloud-optimiser rollback chg-20250101T120000-a1b2c3
+  cloud-optimiser rollback chg-20250101T120000-a1b2c3 --yes`,
+	Args: cobra.MaximumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		j, ok := openJournal()
+		if !ok {
+			return
+		}
+
+		if rollbackList {
+			printJournal(j)
+			return
+		}
+
+		if len(args) == 0 {
+			fmt.Println("Error: a change ID is required (use --list to see journaled changes)")
+			return
+		}
+		changeID := args[0]
+
+		change, ok := j.Get(changeID)
+		if !ok {
+			fmt.Printf("Change %s not found in journal\n", changeID)
+			return
+		}
+
+		// Resolve mock vs real mode
+		useMockMode := resolveMockMode(ctx)
+
+		// Display mode
+		fmt.Fprintln(os.Stderr, "MODE:", map[bool]string{true: "Mock", false: "Real AWS"}[useMockMode])
+		fmt.Fprintln(os.Stderr)
+
+		client, err := awsclient.New(ctx, awsclient.Config{
+			UseMock: useMockMode,
+			Profile: awsProfile,
+		})
+		if err != nil {
+			fmt.Println("Failed to create EC2 client")
+			logging.DebugErr("EC2 client creation failed", err)
+			return
+		}
+
+		// Look up the instance's current state for the drift check
+		instances, err := client.ListInstances(ctx)
+		if err != nil {
+			fmt.Println("Failed to list instances")
+			logging.DebugErr("EC2 ListInstances failed", err)
+			return
+		}
+
+		found := false
+		for _, inst := range instances {
+			if inst.ID != change.InstanceID {
+				continue
+			}
+			found = true
+
+			if _, err := apply.PlanRollback(j, inst, changeID); err != nil {
+				fmt.Printf("Rollback refused: %v\n", err)
+				return
+			}
+
+			fmt.Printf("Rollback %s: %s %s -> %s\n", change.ID, inst.ID, inst.InstanceType, change.OriginalType)
+			if inst.State == "running" {
+				fmt.Println("The instance will be stopped, modified and started again.")
+			}
+
+			if !rollbackYes {
+				fmt.Println("\nRe-run with --yes to perform the rollback.")
+				return
+			}
+
+			c, err := apply.Rollback(ctx, client, j, inst, changeID, operatorName())
+			if err != nil {
+				fmt.Printf("Rollback failed: %v\n", err)
+				if c.ID != "" {
+					fmt.Printf("Journal entry: %s [%s] in %s\n", c.ID, c.State, j.Path())
+				}
+				return
+			}
+
+			fmt.Printf("Rollback complete. Journal entry: %s in %s\n", c.ID, j.Path())
+		}
+
+		if !found {
+			fmt.Printf("Instance %s not found; it may have been terminated\n", change.InstanceID)
+		}
+
+		if client.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(rollbackCmd)
+
+	rollbackCmd.Flags().BoolVar(&rollbackYes, "yes", false, "Perform the rollback (otherwise only preview it)")
+	rollbackCmd.Flags().BoolVar(&rollbackList, "list", false, "List journaled changes")
+	rollbackCmd.Flags().StringVar(&changeOperator, "operator", "", "Operator name recorded in the journal (default: current user)")
+	rollbackCmd.Flags().StringVar(&journalPath, "journal", "", "Path to the change journal (default: ~/cloud-optimiser/journal.json)")
+}
+
+// openJournal opens --journal or the default journal, reporting any failure
+func openJournal() (*journal.Journal, bool) {
+	path := journalPath
+	if path == "" {
+		p, err := journal.DefaultPath()
+		if err != nil {
+			fmt.Printf("Could not locate journal: %v\n", err)
+			return nil, false
+		}
+		path = p
+	}
+
+	j, err := journal.Open(path)
+	if err != nil {
+		fmt.Printf("Could not open journal: %v\n", err)
+		return nil, false
+	}
+	return j, true
+}
+
+// operatorName returns the operator recorded against journaled changes
+func operatorName() string {
+	if changeOperator != "" {
+		return changeOperator
+	}
+	if u, err := user.Current(); err == nil {
+		return u.Username
+	}
+	return "unknown"
+}
+
+// printJournal outputs journaled changes in table format
+func printJournal(j *journal.Journal) {
+	if len(j.Changes) == 0 {
+		fmt.Println("No changes recorded in journal.")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "CHANGE\tKIND\tINSTANCE\tFROM\tTO\tSTATE\tOPERATOR\tUPDATED")
+	for _, c := range j.Changes {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			c.ID,
+			c.Kind,
+			c.InstanceID,
+			c.OriginalType,
+			c.NewType,
+			c.State,
+			c.Operator,
+			c.UpdatedAt.Format("2006-01-02 15:04:05"),
+		)
+	}
+	w.Flush()
+}
@@ -1,10 +1,13 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
 
 	modecmd "github.com/PanaAnt/cloud-optimiser/cmd/mode"
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/config"
 	"github.com/PanaAnt/cloud-optimiser/internal/logging"
 	"github.com/spf13/cobra"
 )
@@ -26,11 +29,12 @@ Supports:
   • Real AWS API mode
   • AWS CLI profiles
   • Filters, sorting, JSON output`,
+	SilenceErrors: true, // Execute prints the error once
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
 		logging.Verbose = debug
 
 		if debug {
-			fmt.Println("Debug mode enabled")
+			fmt.Fprintln(os.Stderr, "Debug mode enabled")
 		}
 
 		if awsProfile != "" {
@@ -43,10 +47,33 @@ Supports:
 	},
 }
 
+// resolveMockMode decides whether this run uses mock data, based on the
+// --use-mock flag, the saved config and whether AWS credentials are usable.
+func resolveMockMode(ctx context.Context) bool {
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Could not load config: %v, defaulting to MOCK mode", err))
+		cfg = config.AppConfig{Mode: "mock"}
+	}
+
+	useMockMode := useMock || cfg.Mode == "mock"
+
+	// AWS readiness check (only if not already in mock mode)
+	if !useMockMode {
+		if err := awsclient.CanUseRealAWS(ctx, awsProfile); err != nil {
+			fmt.Fprintln(os.Stderr, "AWS unavailable – switching to MOCK mode.")
+			logging.DebugErr("AWS readiness check failed", err)
+			useMockMode = true
+		}
+	}
+
+	return useMockMode
+}
+
 // Execute runs the root command
 func Execute() {
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Println(err)
+		fmt.Fprintln(os.Stderr, "Error:", err)
 		os.Exit(1)
 	}
 }
@@ -74,4 +101,4 @@ func init() {
 	)
 
 	rootCmd.AddCommand(modecmd.ModeCmd)
-}
\ No newline at end of file
+}
@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/analyser"
+	"github.com/PanaAnt/cloud-optimiser/internal/export"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+var (
+	scheduleDays     int
+	scheduleTimezone string
+	scheduleOutput   string
+	scheduleRoleArn  string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "Propose off-hours start/stop schedules for non-prod instances",
+	Long: `The schedule command looks at hourly CPU over --days for running instances
+tagged with a non-prod Environment (dev, test, qa, staging, sandbox) and finds
+those only used during part of the week. For each it proposes a start/stop
+window in --timezone, padded by an hour either side, and the monthly saving
+of stopping outside it.
+
+Instances that are busy overnight or most of the week are listed as
+unsuitable with the reason.
+
+--output eventbridge prints EventBridge Scheduler CreateSchedule requests
+(use --role-arn for the execution role); --output instance-scheduler prints
+periods, schedules and Schedule tags for Instance Scheduler on AWS.`,
+	Example: `  cloud-optimiser schedule
+  cloud-optimiser schedule --timezone Europe/London --days 28
+  cloud-optimiser schedule --output eventbridge --role-arn arn:aws:iam::111122223333:role/scheduler`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		switch scheduleOutput {
+		case "table", "json", "eventbridge", "instance-scheduler":
+		default:
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid output %q (valid: table, json, eventbridge, instance-scheduler)", scheduleOutput)
+		}
+		if _, err := time.LoadLocation(scheduleTimezone); err != nil {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid timezone %q: %w", scheduleTimezone, err)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		run, ok := connect(ctx)
+		if !ok {
+			return
+		}
+
+		loc, _ := time.LoadLocation(scheduleTimezone) // validated in PreRunE
+		advice := analyser.AdviseSchedules(ctx, run.Instances, run.CloudWatch, run.CostExplorer, analyser.ScheduleOptions{
+			Days:     scheduleDays,
+			CostDays: costDays,
+			Location: loc,
+		})
+
+		var out any
+		switch scheduleOutput {
+		case "json":
+			out = advice
+		case "eventbridge":
+			groups, err := run.EC2.ListAutoScalingGroups(ctx, scheduledGroups(advice))
+			if err != nil {
+				fmt.Printf("Failed to load Auto Scaling group capacity: %v\n", err)
+				return
+			}
+			schedules, err := export.EventBridgeSchedules(advice, groups, scheduleRoleArn)
+			if err != nil {
+				fmt.Printf("Failed to build EventBridge schedules: %v\n", err)
+				return
+			}
+			out = schedules
+		case "instance-scheduler":
+			out = export.InstanceScheduler(advice)
+		default:
+			printSchedules(advice)
+		}
+
+		if out != nil {
+			b, err := json.MarshalIndent(out, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		}
+
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(scheduleCmd)
+
+	scheduleCmd.Flags().IntVar(&scheduleDays, "days", 14, "Days of hourly CPU history to analyze")
+	scheduleCmd.Flags().StringVar(&scheduleTimezone, "timezone", "UTC", "IANA timezone for the schedule, e.g. Europe/London")
+	scheduleCmd.Flags().StringVar(&scheduleOutput, "output", "table", "Output format: table | json | eventbridge | instance-scheduler")
+	scheduleCmd.Flags().StringVar(&scheduleRoleArn, "role-arn", "", "EventBridge Scheduler execution role (placeholder when empty)")
+	scheduleCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of cost data to analyze")
+}
+
+// printSchedules prints proposed windows and the instances left running
+func printSchedules(advice []model.ScheduleAdvice) {
+	if len(advice) == 0 {
+		fmt.Println("No running non-prod instances found (Environment tag dev, test, qa, staging or sandbox).")
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "INSTANCE\tTYPE\tENV\tSCHEDULE\tHRS/wk\tCPU IN\tCPU OUT\tCOST/mo\tSAVING/mo\tREASON")
+	total := 0.0
+	for _, a := range advice {
+		window := "-"
+		if a.Suitable {
+			window = fmt.Sprintf("%s %02d:00-%02d:00", analyser.DescribeDays(a.Days), a.StartHour, a.StopHour)
+			total += a.MonthlySaving
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%.1f%%\t%.1f%%\t$%.2f\t$%.2f\t%s\n",
+			a.InstanceID, a.InstanceType, a.Environment, window, a.RunningHoursPerWeek,
+			a.BusinessHoursCPU, a.OffHoursCPU, a.MonthlyCost, a.MonthlySaving, a.Reason)
+	}
+	w.Flush()
+
+	fmt.Printf("\nEstimated savings from schedules: $%.2f/mo (times in %s; EBS storage is still billed while stopped)\n", total, scheduleTimezone)
+}
+
+// scheduledGroups lists the Auto Scaling groups with a member to schedule
+func scheduledGroups(advice []model.ScheduleAdvice) []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, a := range advice {
+		if a.Suitable && a.AutoScalingGroup != "" && !seen[a.AutoScalingGroup] {
+			seen[a.AutoScalingGroup] = true
+			names = append(names, a.AutoScalingGroup)
+		}
+	}
+	return names
+}
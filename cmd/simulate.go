@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/analyser"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+	"github.com/spf13/cobra"
+)
+
+var (
+	simInstance string
+	simType     string
+	simOutput   string
+)
+
+var simulateCmd = &cobra.Command{
+	Use:   "simulate",
+	Short: "Simulate moving an instance to a different instance type",
+	Long: `The simulate command answers "what if this instance were a different type?"
+
+It uses the same CPU, memory and cost data as recommend, projects CPU
+utilisation onto the target type using the instance catalog, checks that
+observed peak memory still fits, and prints the monthly cost delta with a
+risk level (low, medium or high).
+
+Use --output json for scripting.`,
+	PreRunE: func(cmd *cobra.Command, args []string) error {
+		if simOutput != "table" && simOutput != "json" {
+			cmd.SilenceUsage = true
+			return fmt.Errorf("invalid output %q (valid: table, json)", simOutput)
+		}
+		return nil
+	},
+	Run: func(cmd *cobra.Command, args []string) {
+		ctx := context.Background()
+
+		run, ok := connect(ctx)
+		if !ok {
+			return
+		}
+
+		var inst *model.EC2Instance
+		for i := range run.Instances {
+			if run.Instances[i].ID == simInstance {
+				inst = &run.Instances[i]
+				break
+			}
+		}
+		if inst == nil {
+			fmt.Printf("Instance %s not found.\n", simInstance)
+			return
+		}
+
+		sim, err := analyser.Simulate(ctx, *inst, simType, run.CloudWatch, run.CostExplorer, analysisOptions())
+		if err != nil {
+			fmt.Printf("Simulation failed: %v\n", err)
+			logging.DebugErr("Simulate failed", err)
+			return
+		}
+
+		if simOutput == "json" {
+			b, err := json.MarshalIndent(sim, "", "  ")
+			if err != nil {
+				fmt.Printf("Failed to marshal JSON: %v\n", err)
+				return
+			}
+			fmt.Println(string(b))
+		} else {
+			printSimulation(sim)
+		}
+
+		if run.EC2.IsMock() {
+			fmt.Fprintln(os.Stderr, "\n[Note: Using mock data]")
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(simulateCmd)
+
+	simulateCmd.Flags().StringVar(&simInstance, "instance", "", "Instance ID to simulate")
+	simulateCmd.Flags().StringVar(&simType, "type", "", "Target instance type, e.g. m6i.large")
+	simulateCmd.Flags().StringVar(&simOutput, "output", "table", "Output format: table | json")
+	simulateCmd.Flags().IntVar(&metricHours, "metric-hours", 24, "Hours of CPU and memory metrics to project from")
+	simulateCmd.Flags().IntVar(&costDays, "cost-days", 30, "Days of cost data to analyze")
+	simulateCmd.Flags().Float64Var(&headroomPct, "headroom", 20, "Percent of memory capacity to keep above the observed peak")
+	simulateCmd.Flags().Float64Var(&targetCPU, "target-cpu", 80, "Projected CPU above this percent raises the risk level")
+	simulateCmd.MarkFlagRequired("instance")
+	simulateCmd.MarkFlagRequired("type")
+}
+
+// printSimulation prints a simulation as aligned key/value lines
+func printSimulation(sim model.Simulation) {
+	fmt.Printf("Simulation: %s  %s -> %s\n\n", sim.InstanceID, sim.CurrentType, sim.TargetType)
+
+	w := tabwriter.NewWriter(os.Stdout, 2, 4, 2, ' ', 0)
+	if sim.CurrentCPU != nil {
+		fmt.Fprintf(w, "CPU now\tavg %.1f%%, p95 %.1f%%, peak %.1f%%\n",
+			sim.CurrentCPU.AvgCPU, sim.CurrentCPU.P95CPU, sim.CurrentCPU.PeakCPU)
+	}
+	if sim.ProjectedCPU != nil {
+		fmt.Fprintf(w, "CPU projected\tavg %.1f%%, p95 %.1f%%, peak %.1f%%\n",
+			sim.ProjectedCPU.AvgCPU, sim.ProjectedCPU.P95CPU, sim.ProjectedCPU.PeakCPU)
+	}
+	if sim.MemoryFits != nil {
+		fmt.Fprintf(w, "Memory\tpeak %.1f%% now, %.1f%% projected\n", sim.PeakMemoryPercent, sim.ProjectedMemory)
+	} else {
+		fmt.Fprintln(w, "Memory\tno data")
+	}
+	fmt.Fprintf(w, "Monthly cost\t$%.2f -> $%.2f (%+.2f)\n", sim.MonthlyCost, sim.ProjectedMonthlyCost, sim.MonthlyDelta)
+	fmt.Fprintf(w, "Risk\t%s\n", strings.ToUpper(sim.Risk))
+	w.Flush()
+
+	if len(sim.Notes) > 0 {
+		fmt.Println()
+		for _, n := range sim.Notes {
+			fmt.Printf(" - %s\n", n)
+		}
+	}
+}
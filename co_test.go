@@ -1,7 +1,10 @@
 package main
 
 import (
+	"encoding/json"
+	"os"
 	"os/exec"
+	"path/filepath"
 	"strings"
 	"testing"
 	"time"
@@ -60,6 +63,61 @@ func TestSmoke_DiscoverMock(t *testing.T) {
 	t.Log("Discover command works with mock data")
 }
 
+// TestSmoke_DiscoverWide verifies the enriched instance fields are shown
+func TestSmoke_DiscoverWide(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "discover", "--use-mock", "--wide")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Discover --wide failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"LIFECYCLE", "eu-west-2a", "mock-web-asg", "on-demand"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected wide output to contain '%s'", expected)
+		}
+	}
+
+	cmd = exec.Command("go", "run", ".", "discover", "--use-mock", "--output", "json")
+	output, err = cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Discover --output json failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{`"availability_zone"`, `"architecture"`, `"volumes"`} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected JSON output to contain %s", expected)
+		}
+	}
+}
+
+// TestSmoke_DiscoverCheckTags verifies tag policy violations and the exit code threshold
+func TestSmoke_DiscoverCheckTags(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "discover", "--use-mock", "--check-tags",
+		"--tag-policy", "testdata/tag_policy.json", "--max-violations", "10")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Discover --check-tags failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"Tag Compliance", "missing", "COMPLIANCE"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected tag check output to contain '%s'", expected)
+		}
+	}
+
+	// The mock instances violate the sample policy, so the default threshold of 0 must fail
+	cmd = exec.Command("go", "run", ".", "discover", "--use-mock", "--check-tags",
+		"--tag-policy", "testdata/tag_policy.json")
+	if _, err := cmd.CombinedOutput(); err == nil {
+		t.Error("Expected non-zero exit when violations exceed the threshold")
+	}
+
+	t.Log("Tag compliance check works")
+}
+
 // TestSmoke_RecommendMock verifies recommend command works with mock data
 func TestSmoke_RecommendMock(t *testing.T) {
 	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock")
@@ -76,6 +134,7 @@ func TestSmoke_RecommendMock(t *testing.T) {
 		"ID",
 		"TYPE",
 		"ACTION",
+		"SUMMARY",
 	}
 
 	for _, expected := range expectedStrings {
@@ -87,10 +146,69 @@ func TestSmoke_RecommendMock(t *testing.T) {
 	t.Log("Recommend command works with mock data")
 }
 
+// TestSmoke_RecommendRules verifies the analyser rules against mock data
+func TestSmoke_RecommendRules(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", "csv")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Recommend command failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "i-0abc1234def567890,t3.medium,running") ||
+		!strings.Contains(string(output), ",Terminate,") {
+		t.Errorf("Expected instance idle over the default 30 days to be recommended for Terminate\nOutput: %s", output)
+	}
+
+	// A shorter idle window is not long enough to be confident it can go
+	short, err := exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", "csv", "--idle-days", "14").Output()
+	if err != nil {
+		t.Fatalf("Recommend --idle-days 14 failed: %v", err)
+	}
+	if !strings.Contains(string(short), ",Stop,") || strings.Contains(string(short), ",Terminate,") {
+		t.Errorf("Expected idle instance to be recommended for Stop over 14 days\nOutput: %s", short)
+	}
+
+	if !strings.Contains(string(output), "i-0987654321fedcba0,m5.large,stopped") ||
+		!strings.Contains(string(output), ",Snapshot & Terminate,") {
+		t.Errorf("Expected long-stopped instance to be recommended for Snapshot & Terminate\nOutput: %s", output)
+	}
+
+	if !strings.Contains(string(output), "i-0c5a1b2c3d4e5f607,c5.xlarge,running") ||
+		!strings.Contains(string(output), ",Migrate to Graviton,c7g.xlarge,") {
+		t.Errorf("Expected x86 instance to be recommended for Graviton\nOutput: %s", output)
+	}
+
+	if !strings.Contains(string(output), ",Upgrade Generation,m6i.large,") {
+		t.Errorf("Expected previous-generation instance to be recommended for an upgrade\nOutput: %s", output)
+	}
+
+	if !strings.Contains(string(output), "i-0e6f7a8b9c0d1e234,r5.large,running") ||
+		!strings.Contains(string(output), ",Downsize,c6i.large,") {
+		t.Errorf("Expected memory-light r5 instance to be moved to another family\nOutput: %s", output)
+	}
+
+	if !strings.Contains(string(output), "i-0f1a2b3c4d5e6f789,t3.large,running") ||
+		!strings.Contains(string(output), ",Switch to Fixed Performance,m6i.large,") {
+		t.Errorf("Expected credit-starved burstable instance to move to a fixed-performance type\nOutput: %s", output)
+	}
+
+	if !strings.Contains(string(output), "i-01a2b3c4d5e6f7a89,m5.large,running") ||
+		!strings.Contains(string(output), ",Switch to Burstable,t3.large,") {
+		t.Errorf("Expected low-utilisation m5 instance to switch to burstable\nOutput: %s", output)
+	}
+
+	if !strings.Contains(string(output), "Projected on c6i.large: avg") {
+		t.Errorf("Expected projected utilisation for suggested types\nOutput: %s", output)
+	}
+
+	t.Log("Analyser rules work with mock data")
+}
+
 // TestSmoke_RecommendJSON verifies JSON output format works
 func TestSmoke_RecommendJSON(t *testing.T) {
 	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", "json")
-	output, err := cmd.CombinedOutput()
+	output, err := cmd.Output()
 
 	if err != nil {
 		t.Fatalf("Recommend JSON command failed: %v\nOutput: %s", err, output)
@@ -102,13 +220,318 @@ func TestSmoke_RecommendJSON(t *testing.T) {
 		t.Error("Expected JSON output with braces")
 	}
 
+	var recs []map[string]any
+	if err := json.Unmarshal(output, &recs); err != nil || len(recs) == 0 {
+		t.Errorf("Expected --output json to be an array of recommendations (err %v)", err)
+	}
+
 	if !strings.Contains(outputStr, "instance_id") {
 		t.Error("Expected JSON to contain 'instance_id' field")
 	}
 
+	if strings.Contains(outputStr, `"summary"`) {
+		t.Error("Expected --output json to stay a bare array of recommendations")
+	}
+
+	cmd = exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", "json-report")
+	output, err = cmd.Output()
+	if err != nil {
+		t.Fatalf("Recommend json-report failed: %v\nOutput: %s", err, output)
+	}
+
+	var doc struct {
+		Summary         map[string]any   `json:"summary"`
+		Recommendations []map[string]any `json:"recommendations"`
+	}
+	if err := json.Unmarshal(output, &doc); err != nil || doc.Summary == nil || len(doc.Recommendations) == 0 {
+		t.Errorf("Expected json-report to hold summary and recommendations (err %v)\nOutput: %s", err, output)
+	}
+
 	t.Log("JSON output format works")
 }
 
+// TestSmoke_JSONStdout verifies machine-readable output on stdout parses without the banner or mock note
+func TestSmoke_JSONStdout(t *testing.T) {
+	commands := [][]string{
+		{"recommend", "--output", "json"},
+		{"recommend", "--output", "json-report"},
+		{"discover", "--output", "json"},
+		{"simulate", "--instance", "i-1234567890abcdef0", "--type", "t3.nano", "--output", "json"},
+		{"commitments", "coverage", "--output", "json"},
+		{"commitments", "recommend", "--output", "json"},
+		{"commitments", "expiring", "--output", "json"},
+		{"schedule", "--output", "json"},
+		{"schedule", "--output", "eventbridge"},
+		{"schedule", "--output", "instance-scheduler"},
+		{"report", "chargeback", "--output", "json"},
+	}
+
+	for _, args := range commands {
+		t.Run(strings.Join(args, " "), func(t *testing.T) {
+			cmd := exec.Command("go", append([]string{"run", ".", "--use-mock"}, args...)...)
+			output, err := cmd.Output()
+			if err != nil {
+				t.Fatalf("Command failed: %v\nOutput: %s", err, output)
+			}
+			if !json.Valid(output) {
+				t.Errorf("Expected stdout to be valid JSON, got: %s", output)
+			}
+		})
+	}
+}
+
+// TestSmoke_RecommendFormats verifies the additional renderers and unknown format rejection
+func TestSmoke_RecommendFormats(t *testing.T) {
+	expected := map[string]string{
+		"csv":      "instance_id,instance_type,state",
+		"markdown": "| ID | Type | State |",
+		"html":     "<table id=\"recs\">",
+	}
+
+	for format, want := range expected {
+		t.Run(format, func(t *testing.T) {
+			cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", format)
+			output, err := cmd.CombinedOutput()
+
+			if err != nil {
+				t.Fatalf("Recommend %s failed: %v\nOutput: %s", format, err, output)
+			}
+
+			if !strings.Contains(string(output), want) {
+				t.Errorf("Expected %s output to contain '%s'", format, want)
+			}
+		})
+	}
+
+	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", "xml")
+	output, err := cmd.CombinedOutput()
+
+	if err == nil {
+		t.Error("Expected error for unknown output format")
+	}
+
+	if !strings.Contains(string(output), "unknown output format") {
+		t.Errorf("Expected unknown format message, got: %s", output)
+	}
+
+	t.Log("Report formats work")
+}
+
+// TestSmoke_Chargeback verifies spend is grouped by tag with an untagged bucket
+func TestSmoke_Chargeback(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "report", "chargeback", "--use-mock", "--by-tag", "Team,Environment", "--reconcile")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Chargeback failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"TEAM", "ENVIRONMENT", "BILLED/mo", "untagged", "TOTAL"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected chargeback output to contain '%s'", expected)
+		}
+	}
+
+	t.Log("Chargeback report works")
+}
+
+// TestSmoke_Simulate verifies a what-if resize projects CPU, cost delta and risk
+func TestSmoke_Simulate(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "simulate", "--use-mock", "--instance", "i-0e6f7a8b9c0d1e234", "--type", "c6i.large")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Simulate failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"CPU projected", "(-34.92)", "LOW"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected simulate output to contain '%s'", expected)
+		}
+	}
+
+	cmd = exec.Command("go", "run", ".", "simulate", "--use-mock", "--instance", "i-0c5a1b2c3d4e5f607", "--type", "c5.large", "--output", "json")
+	output, err = cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Simulate --output json failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{`"projected_cpu"`, `"monthly_delta": -73.73`, `"risk": "high"`} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected JSON output to contain %s", expected)
+		}
+	}
+}
+
+// TestSmoke_RecommendSpot verifies spot suitability and savings are reported for eligible instances
+func TestSmoke_RecommendSpot(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--spot", "--output", "json")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Recommend --spot failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{`"spot"`, `"interruption_risk"`, "in Auto Scaling group mock-web-asg", "m6i.large on spot saves"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected spot output to contain '%s'", expected)
+		}
+	}
+}
+
+// TestSmoke_Commitments verifies RI and Savings Plan coverage is reported and applied to recommendations
+func TestSmoke_Commitments(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "commitments", "coverage", "--use-mock")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Commitments coverage failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"Commitments by family:", "UNUSED HRS/mo", "r4", "compute", "Covered instances:"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected coverage output to contain '%s'", expected)
+		}
+	}
+
+	cmd = exec.Command("go", "run", ".", "recommend", "--use-mock", "--commitments", "--output", "json")
+	output, err = cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Recommend --commitments failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{`"commitment"`, `"covered_pct"`, "so this saves nothing"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected recommend output to contain '%s'", expected)
+		}
+	}
+}
+
+// TestSmoke_CommitmentsExpiring verifies commitments ending within the default horizon are listed with their cost impact
+func TestSmoke_CommitmentsExpiring(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "commitments", "expiring", "--use-mock")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Commitments expiring failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"Commitments expiring within 60 days:", "INCREASE/mo", "1x m5.large", "1x r4.large", "Estimated on-demand increase after expiry"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected expiring output to contain '%s'", expected)
+		}
+	}
+
+	// The r5 Savings Plan ends 83 days out, beyond the default horizon
+	if strings.Contains(string(output), "EC2Instance r5") {
+		t.Errorf("Expected commitments beyond 60 days to be left out\nOutput: %s", output)
+	}
+}
+
+// TestSmoke_CommitmentsRecommend verifies purchase options are sized from the steady-state baseline
+func TestSmoke_CommitmentsRecommend(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "commitments", "recommend", "--use-mock")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Commitments recommend failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"Steady-state on-demand baseline:", "TOTAL", "Compute", "EC2Instance", "BREAK-EVEN", "savings do not add up"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected commitments recommend output to contain '%s'", expected)
+		}
+	}
+}
+
+// TestSmoke_RecommendPattern verifies recurring peaks and cycles are found in hourly CPU history
+func TestSmoke_RecommendPattern(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--pattern-days", "62")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Recommend failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"recurring month-end peak", "only safe outside the peak", "shorter than the weekly cycle"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected recommend output to contain '%s'", expected)
+		}
+	}
+}
+
+// TestSmoke_RecommendDataQuality verifies recommendations on sparse metrics are held for review
+func TestSmoke_RecommendDataQuality(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--min-coverage", "80", "--pattern-days", "0")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Recommend failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"held for review: only 75% of expected CPU datapoints", "1 gap(s), longest 10m"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected recommend output to contain '%s'", expected)
+		}
+	}
+}
+
+// TestSmoke_RecommendConfidence verifies confidence is reported and filtered by --min-confidence
+func TestSmoke_RecommendConfidence(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "recommend", "--use-mock", "--output", "csv", "--min-confidence", "0.75")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Recommend failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "confidence_level") || !strings.Contains(string(output), ",high") {
+		t.Errorf("Expected confidence columns in CSV output\nOutput: %s", output)
+	}
+
+	// The burstable switch rests on a varied series near the thresholds
+	if strings.Contains(string(output), "i-01a2b3c4d5e6f7a89") {
+		t.Errorf("Expected --min-confidence 0.75 to hide the medium confidence recommendation\nOutput: %s", output)
+	}
+
+	cmd = exec.Command("go", "run", ".", "recommend", "--use-mock", "--min-confidence", "2")
+	output, _ = cmd.CombinedOutput()
+	if !strings.Contains(string(output), "invalid --min-confidence") {
+		t.Errorf("Expected out-of-range --min-confidence to be rejected\nOutput: %s", output)
+	}
+}
+
+// TestSmoke_Schedule verifies start/stop schedules are suggested and exported to EventBridge
+func TestSmoke_Schedule(t *testing.T) {
+	cmd := exec.Command("go", "run", ".", "schedule", "--use-mock")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Schedule failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"i-1234567890abcdef0", "MON-FRI", "Estimated savings from schedules"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected schedule output to contain '%s'", expected)
+		}
+	}
+
+	cmd = exec.Command("go", "run", ".", "schedule", "--use-mock", "--output", "eventbridge")
+	output, err = cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Schedule --output eventbridge failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{`"ScheduleExpression"`, "cron(", "updateAutoScalingGroup", `\"DesiredCapacity\":3,\"MinSize\":2`} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected EventBridge output to contain '%s'", expected)
+		}
+	}
+}
+
 // TestSmoke_ModeCommands verifies mode management works
 func TestSmoke_ModeCommands(t *testing.T) {
 	// Set to mock mode
@@ -251,6 +674,114 @@ func TestSmoke_InvalidCommand(t *testing.T) {
 	t.Log("Invalid command handling works")
 }
 
+// TestSmoke_RollbackList verifies the journal can be listed when empty
+func TestSmoke_RollbackList(t *testing.T) {
+	journal := filepath.Join(t.TempDir(), "journal.json")
+	cmd := exec.Command("go", "run", ".", "rollback", "--use-mock", "--list", "--journal", journal)
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Rollback list failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), "No changes recorded") {
+		t.Errorf("Expected empty journal message, got: %s", output)
+	}
+
+	t.Log("Rollback list works")
+}
+
+// TestSmoke_ApplyJournal verifies apply previews without --yes and journals the resize with it
+func TestSmoke_ApplyJournal(t *testing.T) {
+	journal := filepath.Join(t.TempDir(), "journal.json")
+
+	cmd := exec.Command("go", "run", ".", "apply", "i-1234567890abcdef0", "--to", "t3.nano", "--use-mock", "--journal", journal)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Apply preview failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "Re-run with --yes") {
+		t.Errorf("Expected apply preview, got: %s", output)
+	}
+	if _, err := os.Stat(journal); err == nil {
+		t.Error("Expected apply preview not to write the journal")
+	}
+
+	cmd = exec.Command("go", "run", ".", "apply", "i-1234567890abcdef0", "--to", "t3.nano", "--use-mock", "--journal", journal, "--yes", "--operator", "smoke")
+	output, err = cmd.CombinedOutput()
+	if err != nil || !strings.Contains(string(output), "Resize complete") {
+		t.Fatalf("Apply failed: %v\nOutput: %s", err, output)
+	}
+	if !strings.Contains(string(output), "in "+journal) {
+		t.Errorf("Expected apply to report where the journal was written\nOutput: %s", output)
+	}
+
+	cmd = exec.Command("go", "run", ".", "rollback", "--use-mock", "--list", "--journal", journal)
+	output, err = cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("Rollback list failed: %v\nOutput: %s", err, output)
+	}
+	for _, expected := range []string{"i-1234567890abcdef0", "t3.micro", "t3.nano", "completed", "smoke"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected journal listing to contain '%s'\nOutput: %s", expected, output)
+		}
+	}
+}
+
+// TestSmoke_ExportTerraform verifies recommendations are mapped to a Terraform patch
+func TestSmoke_ExportTerraform(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"report.json": `[{"instance_id": "i-1234567890abcdef0", "instance_type": "t3.micro", "suggested_type": "t3.nano"}]`,
+		"main.tf":     "resource \"aws_instance\" \"web\" {\n  instance_type = \"t3.micro\"\n}\n",
+		"terraform.tfstate": `{"version": 4, "resources": [{"mode": "managed", "type": "aws_instance", "name": "web",
+			"instances": [{"attributes": {"id": "i-1234567890abcdef0", "instance_type": "t3.micro"}}]}]}`,
+	}
+	for name, content := range files {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+	}
+
+	cmd := exec.Command("go", "run", ".", "export", "terraform", "--use-mock",
+		"--report", filepath.Join(dir, "report.json"), "--tf-dir", dir)
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Export terraform failed: %v\nOutput: %s", err, output)
+	}
+
+	if !strings.Contains(string(output), `+  instance_type = "t3.nano"`) {
+		t.Errorf("Expected patch updating instance_type, got: %s", output)
+	}
+
+	t.Log("Terraform export works")
+}
+
+// TestSmoke_ExportCloudFormation verifies recommendations are grouped by stack using mock tags
+func TestSmoke_ExportCloudFormation(t *testing.T) {
+	report := filepath.Join(t.TempDir(), "report.json")
+	content := `[{"instance_id": "i-0987654321fedcba0", "instance_type": "m5.large", "suggested_type": "m5.xlarge"}]`
+	if err := os.WriteFile(report, []byte(content), 0644); err != nil {
+		t.Fatalf("Failed to write report: %v", err)
+	}
+
+	cmd := exec.Command("go", "run", ".", "export", "cloudformation", "--use-mock", "--report", report, "--format", "yaml")
+	output, err := cmd.CombinedOutput()
+
+	if err != nil {
+		t.Fatalf("Export cloudformation failed: %v\nOutput: %s", err, output)
+	}
+
+	for _, expected := range []string{"analytics-stack", "AnalyticsInstance", "m5.xlarge"} {
+		if !strings.Contains(string(output), expected) {
+			t.Errorf("Expected CloudFormation output to contain '%s'", expected)
+		}
+	}
+
+	t.Log("CloudFormation export works")
+}
+
 // TestSmoke_QuickRun runs all basic commands quickly
 func TestSmoke_QuickRun(t *testing.T) {
 	commands := []struct {
@@ -268,7 +799,7 @@ func TestSmoke_QuickRun(t *testing.T) {
 			start := time.Now()
 			execCmd := exec.Command("go", "run", ".")
 			execCmd.Args = append(execCmd.Args, cmd.args...)
-			
+
 			output, err := execCmd.CombinedOutput()
 			duration := time.Since(start)
 
@@ -283,4 +814,4 @@ func TestSmoke_QuickRun(t *testing.T) {
 			t.Logf("%s completed in %v", cmd.name, duration)
 		})
 	}
-}
\ No newline at end of file
+}
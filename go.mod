@@ -4,6 +4,7 @@ go 1.24.3
 
 require (
 	github.com/aws/aws-sdk-go-v2 v1.40.0
+	github.com/aws/aws-sdk-go-v2/service/autoscaling v1.62.0
 	github.com/spf13/cobra v1.10.1
 )
 
@@ -27,6 +28,7 @@ require (
 	github.com/aws/aws-sdk-go-v2/service/cloudwatch v1.52.5
 	github.com/aws/aws-sdk-go-v2/service/costexplorer v1.60.2
 	github.com/aws/aws-sdk-go-v2/service/ec2 v1.274.0
+	github.com/aws/aws-sdk-go-v2/service/savingsplans v1.30.5
 	github.com/inconshreveable/mousetrap v1.1.0 // indirect
 	github.com/spf13/pflag v1.0.9 // indirect
 )
@@ -0,0 +1,232 @@
+package analyser
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Thresholds for the burstable credit rule
+const (
+	surplusCreditPrice = 0.05 // USD per vCPU-hour of surplus credits in unlimited mode
+	emptyBalanceRatio  = 0.2  // share of hours with no credits that counts as draining
+)
+
+// creditResult is the outcome of the burstable credit rule
+type creditResult struct {
+	action string
+	target string
+	saving float64
+	reason string
+}
+
+// creditOption is a candidate type and its monthly cost
+type creditOption struct {
+	name string
+	cost float64
+}
+
+// creditAdvice looks past average CPU on burstable types. Instances that spend
+// credits faster than they earn them and run out, or that pay unlimited-mode
+// surplus charges, are moved to a larger burstable size or a fixed-performance
+// type, whichever is cheaper.
+func creditAdvice(
+	ctx context.Context,
+	cw awsclient.CloudWatchClient,
+	inst model.EC2Instance,
+	monthlyCost float64,
+	opts Options,
+) (creditResult, bool) {
+	cat := catalog.Default()
+
+	current, ok := cat.Lookup(inst.InstanceType)
+	if !ok || current.BaselinePct == 0 {
+		return creditResult{}, false
+	}
+
+	credits, err := cw.GetCreditMetrics(ctx, inst.ID, opts.MetricHours)
+	if err != nil || len(credits.Usage) == 0 {
+		logging.DebugErr("Credit metrics unavailable for "+inst.ID, err)
+		return creditResult{}, false
+	}
+
+	earned := current.CreditsPerHour()
+	avgUsage := average(credits.Usage)
+	emptyRatio := fractionBelow(credits.Balance, 1)
+	surplusCost := average(credits.SurplusCharged) / 60 * surplusCreditPrice * hoursPerMonth
+
+	draining := avgUsage > earned && emptyRatio >= emptyBalanceRatio
+	if !draining && surplusCost == 0 {
+		return creditResult{}, false
+	}
+
+	// Scale catalog prices to what this instance is actually billed
+	monthly := func(t catalog.InstanceType) float64 {
+		if monthlyCost > 0 && current.HourlyPrice > 0 {
+			return monthlyCost * t.HourlyPrice / current.HourlyPrice
+		}
+		return t.HourlyPrice * hoursPerMonth
+	}
+
+	h := 1 + opts.HeadroomPct/100
+	currentTotal := monthly(current) + surplusCost
+
+	var larger, fixed *creditOption
+	for name := current.NextLarger; name != ""; name = cat.Larger(name) {
+		t, _ := cat.Lookup(name)
+		if t.CreditsPerHour() >= avgUsage*h {
+			larger = &creditOption{t.Name, monthly(t)}
+			break
+		}
+	}
+	if t, ok := cheapestFixed(cat, current, avgUsage/60*h); ok {
+		fixed = &creditOption{t.Name, monthly(t)}
+	}
+
+	best, action := larger, "Upsize / Scale out"
+	if fixed != nil && (best == nil || fixed.cost < best.cost) {
+		best, action = fixed, "Switch to Fixed Performance"
+	}
+	if best == nil {
+		return creditResult{}, false
+	}
+
+	// In unlimited mode nothing is throttled, so only move if it is cheaper
+	if !draining && best.cost >= currentTotal {
+		return creditResult{}, false
+	}
+
+	var status []string
+	status = append(status, fmt.Sprintf("spending %.0f credits/h vs %.0f earned", avgUsage, earned))
+	if emptyRatio > 0 {
+		status = append(status, fmt.Sprintf("balance empty %.0f%% of hours", emptyRatio*100))
+	}
+	if surplusCost > 0 {
+		status = append(status, fmt.Sprintf("unlimited surplus $%.2f/mo", surplusCost))
+	}
+
+	compare := []string{fmt.Sprintf("%s $%.2f/mo", current.Name, currentTotal)}
+	for _, o := range []*creditOption{larger, fixed} {
+		if o != nil {
+			compare = append(compare, fmt.Sprintf("%s $%.2f/mo", o.name, o.cost))
+		}
+	}
+
+	res := creditResult{
+		action: action,
+		target: best.name,
+		reason: fmt.Sprintf("Burstable credits: %s. Cost comparison: %s.",
+			strings.Join(status, ", "), strings.Join(compare, " vs ")),
+	}
+	if best.cost < currentTotal {
+		res.saving = currentTotal - best.cost
+	} else {
+		res.reason += fmt.Sprintf(" Costs $%.2f/mo more but stops CPU throttling.", best.cost-currentTotal)
+	}
+	return res, true
+}
+
+// cheapestFixed finds the cheapest fixed-performance type with the same
+// architecture that covers the busy vCPUs and at least the current memory
+func cheapestFixed(cat *catalog.Catalog, current catalog.InstanceType, busyVCPU float64) (catalog.InstanceType, bool) {
+	arch := cat.Families[current.Family].Architecture
+
+	var best *catalog.InstanceType
+	for _, t := range cat.Types {
+		f := cat.Families[t.Family]
+		if f.Burstable || f.PreviousGeneration || f.Architecture != arch {
+			continue
+		}
+		if float64(t.VCPU) < busyVCPU || t.MemoryGB < current.MemoryGB {
+			continue
+		}
+		if best == nil || cheaper(t, *best) {
+			best = &t
+		}
+	}
+	if best == nil {
+		return catalog.InstanceType{}, false
+	}
+	return *best, true
+}
+
+// burstableSwitch recommends a cheaper burstable type for a fixed-performance
+// instance with low utilisation. Each candidate keeps at least the current vCPU
+// and memory, and is only accepted if replaying the observed CPU series through
+// its credit model never runs the balance dry.
+func burstableSwitch(
+	inst model.EC2Instance,
+	cpu model.CPUSampleSeries,
+	monthlyCost float64,
+) (creditResult, bool) {
+	cat := catalog.Default()
+
+	current, ok := cat.Lookup(inst.InstanceType)
+	if !ok || current.BaselinePct > 0 || len(cpu.Samples) == 0 || cpu.PeriodSeconds <= 0 {
+		return creditResult{}, false
+	}
+	if average(cpu.Samples) >= lowAvgCPUThreshold {
+		return creditResult{}, false
+	}
+	arch := cat.Families[current.Family].Architecture
+
+	var candidates []catalog.InstanceType
+	for _, t := range cat.Types {
+		f := cat.Families[t.Family]
+		if !f.Burstable || f.PreviousGeneration || f.Architecture != arch {
+			continue
+		}
+		if t.VCPU < current.VCPU || t.MemoryGB < current.MemoryGB || t.HourlyPrice >= current.HourlyPrice {
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+	sort.Slice(candidates, func(i, j int) bool { return cheaper(candidates[i], candidates[j]) })
+
+	for _, t := range candidates {
+		minBalance, ok := simulateCredits(t, cpu, current.VCPU)
+		if !ok {
+			continue
+		}
+		ratio := 1 - t.HourlyPrice/current.HourlyPrice
+		return creditResult{
+			action: "Switch to Burstable",
+			target: t.Name,
+			saving: monthlyCost * ratio,
+			reason: fmt.Sprintf("Average CPU %.1f%%, peak %.1f%%; replaying %d samples on %s (baseline %.0f%% per vCPU) never empties its credit balance (low point %.1f credits). %.0f%% cheaper.",
+				average(cpu.Samples), max(cpu.Samples), len(cpu.Samples), t.Name, t.BaselinePct, minBalance, ratio*100),
+		}, true
+	}
+	return creditResult{}, false
+}
+
+// simulateCredits replays CPU samples observed on a type with sourceVCPU
+// vCPUs through a burstable type's credit model. The balance starts empty, as
+// it does after a type change in standard mode, and is capped at MaxCredits.
+// It returns the lowest balance seen and false if work ever exceeded credits.
+func simulateCredits(t catalog.InstanceType, cpu model.CPUSampleSeries, sourceVCPU int) (float64, bool) {
+	minutes := float64(cpu.PeriodSeconds) / 60
+	earned := t.CreditsPerHour() * minutes / 60
+
+	balance := 0.0
+	low := -1.0
+	for _, pct := range cpu.Samples {
+		// Work is measured in vCPU-minutes at 100%, which is what one credit buys
+		spent := pct / 100 * float64(sourceVCPU) * minutes
+		balance += earned - spent
+		if balance < 0 {
+			return balance, false
+		}
+		balance = min(balance, t.MaxCredits())
+		if low < 0 || balance < low {
+			low = balance
+		}
+	}
+	return low, true
+}
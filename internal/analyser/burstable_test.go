@@ -0,0 +1,51 @@
+package analyser
+
+import (
+	"math"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// repeat returns n copies of v
+func repeat(v float64, n int) []float64 {
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = v
+	}
+	return xs
+}
+
+func TestSimulateCredits(t *testing.T) {
+	// t3.small earns 24 credits an hour, 2 per five minute sample, and banks at most 576
+	small, ok := catalog.Default().Lookup("t3.small")
+	if !ok {
+		t.Fatal("t3.small not in catalog")
+	}
+
+	tests := []struct {
+		name       string
+		samples    []float64
+		sourceVCPU int
+		wantOK     bool
+		wantLow    float64
+	}{
+		{"light load banks credits", repeat(10, 12), 2, true, 1},
+		{"sustained load above baseline runs out", repeat(30, 12), 2, false, -1},
+		{"work scales with the source vCPUs", repeat(15, 12), 4, false, -1},
+		{"same load from fewer vCPUs fits", repeat(15, 12), 2, true, 0.5},
+		// 400 idle samples would earn 800 credits but only 576 are kept, so
+		// the 73rd sample at 100% (8 credits net each) runs the balance out
+		{"balance is capped at a day of earnings", append(repeat(0, 400), repeat(100, 80)...), 2, false, -8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := model.CPUSampleSeries{Samples: tt.samples, PeriodSeconds: 300}
+			low, ok := simulateCredits(small, cpu, tt.sourceVCPU)
+			if ok != tt.wantOK || math.Abs(low-tt.wantLow) > 1e-6 {
+				t.Errorf("simulateCredits = %.2f, %v; want %.2f, %v", low, ok, tt.wantLow, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package analyser
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Confidence scoring
+const (
+	highConfidence   = 0.75 // score at or above this = high
+	mediumConfidence = 0.5  // score at or above this = medium, below = low
+	thresholdMargin  = 10.0 // CPU points from a rule threshold for full confidence
+	factorNoteBelow  = 0.7  // factors scoring below this are listed in the notes
+)
+
+// confidenceFactor is one input to the confidence score
+type confidenceFactor struct {
+	weight float64
+	value  float64 // 0-1
+	note   string  // why the factor lowers confidence
+}
+
+// scoreConfidence rates how far a running instance's recommendation can be
+// trusted from CPU coverage, window length against the longest cycle, sample
+// variance, how close CPU sits to the rule thresholds and whether memory
+// metrics backed a type change.
+func scoreConfidence(rec *model.Recommendation, samples []float64, hasMem bool, opts Options) {
+	if rec.Action == "Unknown" {
+		setConfidence(rec, []confidenceFactor{{1, 0, "CPU metrics failed to load"}})
+		return
+	}
+
+	var factors []confidenceFactor
+
+	coverage := 0.0
+	if q := rec.DataQuality; q != nil && q.ExpectedPoints > 0 {
+		coverage = q.CoveragePct / 100
+		if q.FlatLine {
+			coverage *= 0.5
+		}
+	}
+	factors = append(factors, confidenceFactor{0.3, coverage, fmt.Sprintf("%.0f%% of expected CPU datapoints", coverage*100)})
+	if len(samples) == 0 {
+		setConfidence(rec, factors)
+		return
+	}
+
+	cycle := 24
+	if rec.Pattern != nil {
+		cycle = maxInt(cycle, rec.Pattern.PeriodHours)
+	}
+	window := math.Min(1, float64(opts.MetricHours)/float64(cycle))
+	factors = append(factors, confidenceFactor{0.2, window, fmt.Sprintf("%dh window against a %s cycle", opts.MetricHours, cycleName(cycle))})
+
+	if mean := average(samples); len(samples) > 1 && mean > 0 {
+		cv := stddev(samples) / mean
+		factors = append(factors, confidenceFactor{0.15, 1 / (1 + cv), fmt.Sprintf("CPU varies widely (coefficient of variation %.2f)", cv)})
+	}
+
+	distance := math.Min(math.Abs(rec.AvgCPU-lowAvgCPUThreshold), math.Abs(rec.AvgCPU-highAvgCPUThreshold))
+	distance = math.Min(distance, math.Abs(rec.PeakCPU-lowPeakCPUThreshold))
+	if rec.Projection != nil && opts.TargetCPU > 0 {
+		distance = math.Min(distance, math.Abs(rec.Projection.P95CPU-opts.TargetCPU))
+	}
+	factors = append(factors, confidenceFactor{0.2, math.Min(1, distance/thresholdMargin),
+		fmt.Sprintf("CPU within %.1f points of a rule threshold", distance)})
+
+	if rec.SuggestedType != "" && rec.SuggestedType != rec.InstanceType {
+		memory := 1.0
+		if !hasMem {
+			memory = 0.5
+		}
+		factors = append(factors, confidenceFactor{0.15, memory, "no memory metrics to check the new type fits"})
+	}
+
+	setConfidence(rec, factors)
+}
+
+// scoreStoppedConfidence rates a stopped instance's recommendation, which
+// rests on how long it has been stopped rather than on metrics
+func scoreStoppedConfidence(rec *model.Recommendation) {
+	if rec.Action == "Review" { // stop time unknown
+		setConfidence(rec, []confidenceFactor{{1, 0.4, "stop time unknown"}})
+		return
+	}
+	setConfidence(rec, []confidenceFactor{{1, 1, ""}})
+}
+
+// setConfidence combines weighted factors into the score and level, noting
+// the factors that held it down
+func setConfidence(rec *model.Recommendation, factors []confidenceFactor) {
+	var sum, weights float64
+	rec.ConfidenceNotes = nil
+	for _, f := range factors {
+		sum += f.weight * f.value
+		weights += f.weight
+		if f.value < factorNoteBelow && f.note != "" {
+			rec.ConfidenceNotes = append(rec.ConfidenceNotes, f.note)
+		}
+	}
+	if weights > 0 {
+		rec.Confidence = math.Round(sum/weights*100) / 100
+	}
+	switch {
+	case rec.Confidence >= highConfidence:
+		rec.ConfidenceLevel = "high"
+	case rec.Confidence >= mediumConfidence:
+		rec.ConfidenceLevel = "medium"
+	default:
+		rec.ConfidenceLevel = "low"
+	}
+}
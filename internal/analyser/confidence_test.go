@@ -0,0 +1,57 @@
+package analyser
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func TestScoreConfidence(t *testing.T) {
+	full := &model.DataQuality{ExpectedPoints: 288, ReceivedPoints: 288, CoveragePct: 100}
+	stuck := &model.DataQuality{ExpectedPoints: 288, ReceivedPoints: 288, CoveragePct: 100, FlatLine: true}
+	steady := repeat(5, 288)
+	downsize := func(q *model.DataQuality) model.Recommendation {
+		return model.Recommendation{Action: "Downsize", InstanceType: "m5.large", SuggestedType: "t3.large", AvgCPU: 5, PeakCPU: 5, DataQuality: q}
+	}
+	nearThreshold := model.Recommendation{Action: "Keep as-is", InstanceType: "m5.large", AvgCPU: 19, PeakCPU: 60,
+		DataQuality: full, Pattern: &model.Seasonality{PeriodHours: scheduleHoursPerWeek}}
+
+	tests := []struct {
+		name      string
+		rec       model.Recommendation
+		samples   []float64
+		hasMem    bool
+		want      float64
+		level     string
+		wantNotes []string
+	}{
+		{"metrics failed", model.Recommendation{Action: "Unknown"}, nil, false, 0, "low", []string{"CPU metrics failed to load"}},
+		{"full, steady and clear of thresholds", downsize(full), steady, true, 1, "high", nil},
+		// Memory weighs 0.15 of the score and counts half without metrics
+		{"type change without memory metrics", downsize(full), steady, false, 0.93, "high", []string{"no memory metrics"}},
+		// Coverage weighs 0.3 and a flat line halves it
+		{"stuck metric", downsize(stuck), steady, true, 0.85, "high", []string{"50% of expected CPU datapoints"}},
+		// (0.3 + 0.2*24/168 + 0.15 + 0.2*0.1) / 0.85
+		{"weekly cycle and near a threshold", nearThreshold, steady, false, 0.59, "medium", []string{"24h window against a weekly cycle", "CPU within 1.0 points"}},
+		{"no datapoints", model.Recommendation{Action: "Keep as-is"}, nil, false, 0, "low", []string{"0% of expected CPU datapoints"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := tt.rec
+			scoreConfidence(&rec, tt.samples, tt.hasMem, Options{MetricHours: 24, TargetCPU: 80})
+			if math.Abs(rec.Confidence-tt.want) > 1e-9 || rec.ConfidenceLevel != tt.level {
+				t.Errorf("confidence = %.2f %s, want %.2f %s (notes %q)", rec.Confidence, rec.ConfidenceLevel, tt.want, tt.level, rec.ConfidenceNotes)
+			}
+			if len(rec.ConfidenceNotes) != len(tt.wantNotes) {
+				t.Fatalf("notes = %q, want %d matching %q", rec.ConfidenceNotes, len(tt.wantNotes), tt.wantNotes)
+			}
+			for i, want := range tt.wantNotes {
+				if !strings.Contains(rec.ConfidenceNotes[i], want) {
+					t.Errorf("note %d = %q, want it to contain %q", i, rec.ConfidenceNotes[i], want)
+				}
+			}
+		})
+	}
+}
@@ -3,8 +3,11 @@ package analyser
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
 	"github.com/PanaAnt/cloud-optimiser/internal/model"
 )
 
@@ -17,33 +20,54 @@ const (
 	downsizeSavingEstimate = 0.3  // 30% estimated savings for downsize
 )
 
+// Options controls the analysis windows and rule tuning.
+type Options struct {
+	MetricHours int     // CPU window for utilisation rules
+	CostDays    int     // cost window passed to Cost Explorer
+	IdleDays    int     // long window used to confirm idle instances
+	StoppedDays int     // stopped longer than this = snapshot-and-terminate candidate
+	HeadroomPct float64 // capacity kept above observed peaks when right-sizing
+	TargetCPU   float64 // reject suggestions projected to run above this p95 CPU
+	PatternDays int     // hourly history searched for cycles and recurring peaks (0 disables)
+	MinCoverage float64 // percent of expected CPU datapoints below which actions are held for review (0 disables)
+}
+
 // AnalyseInstances fetches metrics + costs and generates recommendations.
 func AnalyseInstances(
 	ctx context.Context,
 	instances []model.EC2Instance,
 	cw awsclient.CloudWatchClient,
 	ce awsclient.CostExplorerClient,
-	metricHours int,
-	costDays int,
+	opts Options,
 ) ([]model.Recommendation, error) {
 	var recs []model.Recommendation
+	now := time.Now().UTC()
 
 	for _, inst := range instances {
 		if inst.State == "terminated" {
 			continue
 		}
 
+		// Stopped instances have no CPU to judge; their cost is lingering EBS
+		if inst.State == "stopped" {
+			rec := analyseStopped(inst, fetchCost(ctx, ce, inst.ID, opts.CostDays), opts, now)
+			scoreStoppedConfidence(&rec)
+			recs = append(recs, rec)
+			continue
+		}
+
 		// 1) Fetch CPU metrics
-		cpuSeries, err := cw.GetCpuUtilisation(ctx, inst.ID, metricHours)
+		cpuSeries, err := cw.GetCpuUtilisation(ctx, inst.ID, opts.MetricHours)
 		if err != nil {
-			
-			recs = append(recs, model.Recommendation{
+			rec := model.Recommendation{
 				InstanceID:   inst.ID,
 				InstanceType: inst.InstanceType,
 				State:        inst.State,
 				Action:       "Unknown",
 				Reason:       fmt.Sprintf("Failed to load CPU metrics: %v", err),
-			})
+			}
+			scoreConfidence(&rec, nil, false, opts)
+			recs = append(recs, rec)
 			continue
 		}
 
@@ -52,13 +76,24 @@ func AnalyseInstances(
 		idleRatio := fractionBelow(cpuSeries.Samples, idleCPUThreshold)
 
 		// Fetch cost data
-		cost, err := ce.GetInstanceCost(ctx, inst.ID, costDays)
-		if err != nil {
-			// Cost failure: still produce recommendation without savings
-			cost = model.CostData{
-				InstanceID:  inst.ID,
-				MonthlyCost: 0,
-				HourlyCost:  0,
+		cost := fetchCost(ctx, ce, inst.ID, opts.CostDays)
+
+		// Memory is only published by the CloudWatch agent; without it sizing stays CPU-only
+		var peakMem float64
+		var fit rightsizeResult
+		hasMem, canFit := false, false
+		if len(cpuSeries.Samples) > 0 {
+			peakMem, hasMem = fetchPeakMemory(ctx, cw, inst.ID, opts.MetricHours)
+		}
+		netFailed := false
+		if hasMem {
+			// Without the network peak a smaller type could be too narrow, so none is suggested
+			net, err := cw.GetNetworkActivity(ctx, inst.ID, opts.MetricHours)
+			if err != nil {
+				logging.DebugErr("Network metrics unavailable for "+inst.ID, err)
+				netFailed = true
+			} else {
+				fit, canFit = rightsize(inst, cpuSeries, peakMem, net, opts, cost.MonthlyCost)
 			}
 		}
 
@@ -68,10 +103,32 @@ func AnalyseInstances(
 		estimatedSaving := 0.0
 		reason := ""
 
+		// Blocked Graviton advice is still noted when the instance is kept
+		grav, gravOK := gravitonAdvice(inst, cost.MonthlyCost)
+
 		if len(cpuSeries.Samples) == 0 {
 			action = "Review / Potentially Stop"
 			reason = "No CPU data available; instance may be idle or not sending metrics."
-		} else if avgCPU < lowAvgCPUThreshold && peakCPU < lowPeakCPUThreshold {
+		} else if idle, ok := checkIdle(ctx, cw, inst, idleRatio, opts); ok {
+			action = idle.action
+			reason = idle.reason
+			estimatedSaving = cost.MonthlyCost // stopping or terminating saves the full compute cost
+		} else if c, ok := creditAdvice(ctx, cw, inst, cost.MonthlyCost, opts); ok {
+			action = c.action
+			suggestedType = c.target
+			estimatedSaving = c.saving
+			reason = c.reason
+		} else if canFit {
+			action = "Downsize"
+			suggestedType = fit.target.Name
+			estimatedSaving = fit.saving
+			reason = fmt.Sprintf("Peak CPU %.1f%%, peak memory %.1f%%; %s", peakCPU, peakMem, fit.reason)
+		} else if b, ok := burstableSwitch(inst, cpuSeries, cost.MonthlyCost); ok {
+			action = b.action
+			suggestedType = b.target
+			estimatedSaving = b.saving
+			reason = b.reason
+		} else if !hasMem && avgCPU < lowAvgCPUThreshold && peakCPU < lowPeakCPUThreshold {
 			action = "Downsize"
 			suggestedType = downsizeInstanceType(inst.InstanceType)
 			if cost.MonthlyCost > 0 && suggestedType != inst.InstanceType {
@@ -79,18 +136,52 @@ func AnalyseInstances(
 			}
 			reason = fmt.Sprintf("Average CPU %.1f%%, peak %.1f%%, idle %.0f%% of samples; strong downsize candidate.",
 				avgCPU, peakCPU, idleRatio*100)
+			if currentGenerationType(inst.InstanceType) != inst.InstanceType {
+				// Priced from the catalog, as the size and generation both change
+				estimatedSaving = 0
+				cur, _ := catalog.Default().Lookup(inst.InstanceType)
+				if t, ok := catalog.Default().Lookup(suggestedType); ok && cur.HourlyPrice > 0 && t.HourlyPrice < cur.HourlyPrice {
+					estimatedSaving = cost.MonthlyCost * (1 - t.HourlyPrice/cur.HourlyPrice)
+				}
+				reason += fmt.Sprintf(" %s is a previous-generation type, so the size is taken in its successor family as %s.",
+					inst.InstanceType, suggestedType)
+			}
 		} else if avgCPU > highAvgCPUThreshold {
 			action = "Upsize / Scale out"
 			suggestedType = upsizeInstanceType(inst.InstanceType)
 			reason = fmt.Sprintf("Average CPU %.1f%% over %d hours; instance appears heavily utilized.",
-				avgCPU, metricHours)
+				avgCPU, opts.MetricHours)
+			if currentGenerationType(inst.InstanceType) != inst.InstanceType {
+				reason += fmt.Sprintf(" %s is a previous-generation type, so the size is taken in its successor family as %s.",
+					inst.InstanceType, suggestedType)
+			}
+		} else if g, ok := generationAdvice(inst, cost.MonthlyCost); ok {
+			action = "Upgrade Generation"
+			suggestedType = g.target
+			estimatedSaving = g.saving
+			reason = fmt.Sprintf("Average CPU %.1f%%, peak %.1f%%; utilization appears reasonable. %s",
+				avgCPU, peakCPU, g.describe(inst.InstanceType))
+		} else if gravOK && len(grav.blockers) == 0 {
+			action = "Migrate to Graviton"
+			suggestedType = grav.target
+			estimatedSaving = grav.saving
+			reason = fmt.Sprintf("Average CPU %.1f%%, peak %.1f%%; utilization appears reasonable. %s",
+				avgCPU, peakCPU, grav.describe())
 		} else {
 			action = "Keep as-is"
 			reason = fmt.Sprintf("Average CPU %.1f%%, peak %.1f%%; utilization appears reasonable.",
 				avgCPU, peakCPU)
+			if netFailed {
+				reason += " Network metrics failed to load, so no smaller type was considered."
+			} else if hasMem {
+				reason += fmt.Sprintf(" Peak memory %.1f%% leaves no cheaper fit.", peakMem)
+			}
+			if gravOK {
+				reason += " " + grav.describe()
+			}
 		}
 
-		recs = append(recs, model.Recommendation{
+		rec := model.Recommendation{
 			InstanceID:      inst.ID,
 			InstanceType:    inst.InstanceType,
 			State:           inst.State,
@@ -102,7 +193,15 @@ func AnalyseInstances(
 			SuggestedType:   suggestedType,
 			EstimatedSaving: estimatedSaving,
 			Reason:          reason,
-		})
+		}
+		checkProjection(&rec, cpuSeries.Samples, opts.TargetCPU)
+		if len(cpuSeries.Samples) > 0 {
+			checkPattern(ctx, &rec, cw, opts)
+		}
+		applyQuality(&rec, assessQuality(cpuSeries, opts.MetricHours), opts.MinCoverage)
+		scoreConfidence(&rec, cpuSeries.Samples, hasMem, opts)
+
+		recs = append(recs, rec)
 	}
 
 	return recs, nil
@@ -110,6 +209,31 @@ func AnalyseInstances(
 
 // --- Helper functions ---
 
+// fetchCost loads an instance's cost; on failure it returns zero cost so
+// recommendations are still produced, just without savings
+func fetchCost(ctx context.Context, ce awsclient.CostExplorerClient, instanceID string, days int) model.CostData {
+	cost, err := ce.GetInstanceCost(ctx, instanceID, days)
+	if err != nil {
+		logging.DebugErr("Cost data unavailable for "+instanceID, err)
+		return model.CostData{InstanceID: instanceID}
+	}
+	return cost
+}
+
+// fetchPeakMemory returns the peak memory utilisation, or false when the
+// CloudWatch agent is not publishing memory metrics
+func fetchPeakMemory(ctx context.Context, cw awsclient.CloudWatchClient, instanceID string, hours int) (float64, bool) {
+	mem, err := cw.GetMemoryUtilisation(ctx, instanceID, hours)
+	if err != nil {
+		logging.DebugErr("Memory metrics unavailable for "+instanceID, err)
+		return 0, false
+	}
+	if len(mem.Samples) == 0 {
+		return 0, false
+	}
+	return max(mem.Samples), true
+}
+
 // average calculates the mean of a slice of floats
 func average(xs []float64) float64 {
 	if len(xs) == 0 {
@@ -150,45 +274,31 @@ func fractionBelow(xs []float64, threshold float64) float64 {
 	return float64(count) / float64(len(xs))
 }
 
-// downsizeInstanceType suggests a smaller instance type
-// TODO: Replace with JSON catalog for comprehensive instance type support
+// downsizeInstanceType suggests the next smaller size in the same family,
+// moving a previous-generation type to its successor family first
 func downsizeInstanceType(current string) string {
-	switch current {
-	case "t3.micro":
-		return "t3.nano"
-	case "t3.small":
-		return "t3.micro"
-	case "t3.medium":
-		return "t3.small"
-	case "m5.large":
-		return "m5.medium"
-	case "m5.xlarge":
-		return "m5.large"
-	case "m5.2xlarge":
-		return "m5.xlarge"
-	default:
-		return current // unknown: keep same type
+	base := currentGenerationType(current)
+	if smaller := catalog.Default().Smaller(base); smaller != "" {
+		return smaller
 	}
+	return base // unknown or already smallest: keep same size
 }
 
-// upsizeInstanceType suggests a larger instance type
-// TODO: Replace with JSON catalog for comprehensive instance type support
+// upsizeInstanceType suggests the next larger size in the same family,
+// moving a previous-generation type to its successor family first
 func upsizeInstanceType(current string) string {
-	switch current {
-	case "t3.nano":
-		return "t3.micro"
-	case "t3.micro":
-		return "t3.small"
-	case "t3.small":
-		return "t3.medium"
-	case "m5.medium":
-		return "m5.large"
-	case "m5.large":
-		return "m5.xlarge"
-	case "m5.xlarge":
-		return "m5.2xlarge"
-	default:
-		return current // unknown: keep same type
+	base := currentGenerationType(current)
+	if larger := catalog.Default().Larger(base); larger != "" {
+		return larger
 	}
+	return base // unknown or already largest: keep same size
 }
 
+// currentGenerationType returns the same size in the successor family of a
+// previous-generation type, or the type itself
+func currentGenerationType(name string) string {
+	if t, ok := catalog.Default().CurrentGeneration(name); ok {
+		return t.Name
+	}
+	return name
+}
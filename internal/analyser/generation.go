@@ -0,0 +1,41 @@
+package analyser
+
+import (
+	"fmt"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// generationResult is the outcome of the newer-generation rule
+type generationResult struct {
+	target string
+	saving float64 // per month; may be zero when prices match
+}
+
+// generationAdvice recommends the current-generation equivalent for instances
+// on a family the catalog marks as previous generation.
+func generationAdvice(inst model.EC2Instance, monthlyCost float64) (generationResult, bool) {
+	cat := catalog.Default()
+
+	current, ok := cat.Lookup(inst.InstanceType)
+	if !ok {
+		return generationResult{}, false
+	}
+	target, ok := cat.CurrentGeneration(inst.InstanceType)
+	if !ok {
+		return generationResult{}, false
+	}
+
+	res := generationResult{target: target.Name}
+	if current.HourlyPrice > 0 && target.HourlyPrice < current.HourlyPrice {
+		res.saving = monthlyCost * (1 - target.HourlyPrice/current.HourlyPrice)
+	}
+	return res, true
+}
+
+// describe explains the advice for the recommendation reason
+func (g generationResult) describe(current string) string {
+	return fmt.Sprintf("%s is a previous-generation type; %s offers better price-performance on current hardware.",
+		current, g.target)
+}
@@ -0,0 +1,25 @@
+package analyser
+
+import "testing"
+
+func TestResizeInstanceType(t *testing.T) {
+	tests := []struct {
+		current string
+		smaller string
+		larger  string
+	}{
+		{"t3.medium", "t3.small", "t3.large"},
+		// Previous-generation types move to their successor family first
+		{"m4.xlarge", "m6i.large", "m6i.2xlarge"},
+		{"m4.large", "m6i.large", "m6i.xlarge"},
+		{"unknown.large", "unknown.large", "unknown.large"},
+	}
+	for _, tt := range tests {
+		if got := downsizeInstanceType(tt.current); got != tt.smaller {
+			t.Errorf("downsizeInstanceType(%s) = %s, want %s", tt.current, got, tt.smaller)
+		}
+		if got := upsizeInstanceType(tt.current); got != tt.larger {
+			t.Errorf("upsizeInstanceType(%s) = %s, want %s", tt.current, got, tt.larger)
+		}
+	}
+}
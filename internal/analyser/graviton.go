@@ -0,0 +1,58 @@
+package analyser
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// gravitonResult is the outcome of the Graviton migration advisor
+type gravitonResult struct {
+	target   string
+	percent  float64  // catalog price reduction
+	saving   float64  // per month, percent applied to actual cost
+	blockers []string // reasons the migration cannot be recommended as-is
+}
+
+// gravitonAdvice proposes the same-size arm64 equivalent for x86 instances whose
+// family has one in the catalog, and lists anything blocking the move.
+func gravitonAdvice(inst model.EC2Instance, monthlyCost float64) (gravitonResult, bool) {
+	cat := catalog.Default()
+
+	current, ok := cat.Lookup(inst.InstanceType)
+	if !ok || inst.Architecture == "arm64" {
+		return gravitonResult{}, false
+	}
+	target, ok := cat.GravitonEquivalent(inst.InstanceType)
+	if !ok || target.HourlyPrice >= current.HourlyPrice {
+		return gravitonResult{}, false
+	}
+
+	ratio := 1 - target.HourlyPrice/current.HourlyPrice
+	res := gravitonResult{
+		target:  target.Name,
+		percent: ratio * 100,
+		saving:  monthlyCost * ratio,
+	}
+
+	if strings.Contains(strings.ToLower(inst.Platform), "windows") {
+		res.blockers = append(res.blockers, "Windows has no arm64 AMIs on EC2")
+	}
+	if cat.IsX86OnlyImage(inst.ImageName) {
+		res.blockers = append(res.blockers, fmt.Sprintf("AMI %s is x86-only", inst.ImageName))
+	}
+
+	return res, true
+}
+
+// describe explains the advice for the recommendation reason
+func (g gravitonResult) describe() string {
+	if len(g.blockers) > 0 {
+		return fmt.Sprintf("Graviton %s would save $%.2f/mo but is blocked: %s.",
+			g.target, g.saving, strings.Join(g.blockers, "; "))
+	}
+	return fmt.Sprintf("Graviton %s is %.0f%% cheaper; rebuild the AMI and dependencies for arm64 before migrating.",
+		g.target, g.percent)
+}
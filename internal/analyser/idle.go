@@ -0,0 +1,92 @@
+package analyser
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Thresholds for the idle rule
+const (
+	idleRatioThreshold      = 0.90 // share of CPU samples below idleCPUThreshold
+	idleNetworkBytesPerDay  = 5e6  // combined in+out traffic below this is idle
+	idleMaxConnections      = 1.0  // allow a stray monitoring/SSH connection
+	terminateRatioThreshold = 0.99 // near-total CPU idleness required to terminate
+	terminateNetworkPerDay  = 1e6  // and almost no traffic
+	terminateMinDays        = 30   // and a long enough window to be confident
+)
+
+// idleResult is the outcome of the idle rule
+type idleResult struct {
+	action string
+	reason string
+}
+
+// checkIdle confirms whether a running instance is idle over the long idle window,
+// combining the CPU idle ratio, network bytes and connection counts.
+// The short-window idleRatio is used as a cheap pre-check before extra metric calls.
+func checkIdle(
+	ctx context.Context,
+	cw awsclient.CloudWatchClient,
+	inst model.EC2Instance,
+	idleRatio float64,
+	opts Options,
+) (idleResult, bool) {
+	if inst.State != "running" || opts.IdleDays <= 0 || idleRatio < idleRatioThreshold {
+		return idleResult{}, false
+	}
+
+	hours := opts.IdleDays * 24
+
+	longCPU, err := cw.GetCpuUtilisation(ctx, inst.ID, hours)
+	if err != nil || len(longCPU.Samples) == 0 {
+		logging.DebugErr("Idle check: long-window CPU unavailable for "+inst.ID, err)
+		return idleResult{}, false
+	}
+	longRatio := fractionBelow(longCPU.Samples, idleCPUThreshold)
+	if longRatio < idleRatioThreshold {
+		return idleResult{}, false
+	}
+
+	net, err := cw.GetNetworkActivity(ctx, inst.ID, hours)
+	if err != nil {
+		logging.DebugErr("Idle check: network metrics unavailable for "+inst.ID, err)
+		return idleResult{}, false
+	}
+
+	bytesPerDay := (net.NetworkInBytes + net.NetworkOutBytes) / float64(opts.IdleDays)
+	if bytesPerDay >= idleNetworkBytesPerDay {
+		return idleResult{}, false
+	}
+
+	connNote := "connection data unavailable (CloudWatch agent not reporting)"
+	peakConns := 0.0
+	if len(net.Connections) > 0 {
+		peakConns = max(net.Connections)
+		if peakConns > idleMaxConnections {
+			return idleResult{}, false
+		}
+		connNote = fmt.Sprintf("peak %.0f established connection(s)", peakConns)
+	}
+
+	evidence := fmt.Sprintf("CPU idle %.0f%% of samples, %.1f MB/day network, %s over %d days",
+		longRatio*100, bytesPerDay/1e6, connNote, opts.IdleDays)
+
+	if longRatio >= terminateRatioThreshold &&
+		bytesPerDay < terminateNetworkPerDay &&
+		len(net.Connections) > 0 && peakConns == 0 &&
+		opts.IdleDays >= terminateMinDays {
+		return idleResult{
+			action: "Terminate",
+			reason: evidence + "; instance appears abandoned. Create an AMI or EBS snapshots before terminating.",
+		}, true
+	}
+
+	return idleResult{
+		action: "Stop",
+		reason: evidence + "; instance appears idle. Attached EBS volumes are still billed while stopped.",
+	}, true
+}
@@ -0,0 +1,217 @@
+package analyser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Seasonality detection over hourly CPU history
+const (
+	patternMinPoints   = 48   // two days of hourly points
+	patternACF         = 0.5  // autocorrelation at a cycle's lag at or above this = seasonal
+	patternWeeklyGain  = 0.1  // weekly ACF must beat daily ACF by this for weekdays and weekends to differ
+	peakMinCPU         = 30.0 // peaks are at least this busy
+	peakFactor         = 1.5  // and this many times the 90th percentile
+	dailyPeakShare     = 0.5  // a peak starting at the same hour on this share of days is daily
+	peakMinOccurrences = 2    // a peak must come back to be recurring
+)
+
+// checkPattern looks for cycles and recurring peaks in hourly CPU history. It
+// warns when the analysis window is shorter than the longest cycle and rejects
+// cheaper types that would only cope outside a recurring peak.
+func checkPattern(ctx context.Context, rec *model.Recommendation, cw awsclient.CloudWatchClient, opts Options) {
+	if opts.PatternDays <= 0 || rec.State != "running" {
+		return
+	}
+	history, err := cw.GetHourlyCpu(ctx, rec.InstanceID, opts.PatternDays)
+	if err != nil {
+		logging.DebugErr("Pattern check: hourly CPU unavailable for "+rec.InstanceID, err)
+		return
+	}
+	pattern, peakSamples, ok := detectPattern(history.Points)
+	if !ok || pattern.PeriodHours == 0 {
+		return
+	}
+	rec.Pattern = &pattern
+
+	if rec.SuggestedType != "" && rec.SuggestedType != rec.InstanceType && rec.EstimatedSaving > 0 {
+		for i, peak := range pattern.Peaks {
+			proj, ok := projectUtilisation(peakSamples[i], rec.InstanceType, rec.SuggestedType)
+			if !ok {
+				continue
+			}
+			hot := proj.PeakCPU >= 100
+			if opts.TargetCPU > 0 {
+				hot = proj.P95CPU > opts.TargetCPU
+			}
+			if !hot {
+				continue
+			}
+			rec.Reason = fmt.Sprintf("%s to %s rejected: the recurring %s peak (%s, seen %d times, up to %.1f%% CPU) would run at p95 %.1f%% on %s; it is only safe outside the peak.",
+				rec.Action, rec.SuggestedType, peak.Pattern, peak.When, peak.Occurrences, peak.PeakCPU, proj.P95CPU, rec.SuggestedType)
+			rec.Action = "Keep as-is"
+			rec.SuggestedType = ""
+			rec.EstimatedSaving = 0
+			rec.Projection = nil
+			break
+		}
+	}
+
+	if pattern.PeriodHours > opts.MetricHours {
+		rec.Reason += fmt.Sprintf(" The %dh analysis window is shorter than the %s cycle seen over %.0f days; use --metric-hours %d to cover it.",
+			opts.MetricHours, cycleName(pattern.PeriodHours), pattern.Days, pattern.PeriodHours)
+	}
+}
+
+// detectPattern finds daily and weekly seasonality from autocorrelation and
+// groups CPU peaks that recur at the same hour, weekday or month end. The
+// samples of each recurring peak are returned alongside it.
+func detectPattern(points []model.CPUPoint) (model.Seasonality, [][]float64, bool) {
+	if len(points) < patternMinPoints {
+		return model.Seasonality{}, nil, false
+	}
+	sorted := append([]model.CPUPoint(nil), points...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	byHour := make(map[time.Time]float64, len(sorted))
+	values := make([]float64, 0, len(sorted))
+	for _, p := range sorted {
+		byHour[p.Time.UTC().Truncate(time.Hour)] = p.Value
+		values = append(values, p.Value)
+	}
+
+	s := model.Seasonality{Days: sorted[len(sorted)-1].Time.Sub(sorted[0].Time).Hours()/24 + 1.0/24}
+
+	// Autocorrelation at a lag, over the pairs of hours both present
+	mean, sd := average(values), stddev(values)
+	acf := func(lag int) float64 {
+		if sd == 0 {
+			return 0
+		}
+		sum, n := 0.0, 0
+		for t, v := range byHour {
+			if w, ok := byHour[t.Add(time.Duration(lag)*time.Hour)]; ok {
+				sum += (v - mean) * (w - mean)
+				n++
+			}
+		}
+		if n == 0 {
+			return 0
+		}
+		return sum / float64(n) / (sd * sd)
+	}
+	if s.Days >= 2 {
+		s.DailyACF = acf(24)
+		s.Daily = s.DailyACF >= patternACF
+	}
+	if s.Days >= 14 {
+		s.WeeklyACF = acf(scheduleHoursPerWeek)
+		s.Weekly = s.WeeklyACF >= patternACF && s.WeeklyACF-s.DailyACF >= patternWeeklyGain
+	}
+
+	// Peaks: runs of consecutive hours well above normal load
+	type event struct {
+		start   time.Time
+		last    time.Time
+		samples []float64
+	}
+	threshold := math.Max(peakMinCPU, peakFactor*percentile(values, 90))
+	var events []*event
+	for _, p := range sorted {
+		if p.Value < threshold {
+			continue
+		}
+		if n := len(events); n > 0 && p.Time.Sub(events[n-1].last) <= time.Hour {
+			events[n-1].last = p.Time
+			events[n-1].samples = append(events[n-1].samples, p.Value)
+			continue
+		}
+		events = append(events, &event{start: p.Time, last: p.Time, samples: []float64{p.Value}})
+	}
+
+	startsAt := map[int]int{}
+	for _, e := range events {
+		startsAt[e.start.UTC().Hour()]++
+	}
+
+	type group struct {
+		peak    model.RecurringPeak
+		seen    map[string]bool
+		samples []float64
+	}
+	groups := map[string]*group{}
+	var keys []string
+	for _, e := range events {
+		t := e.start.UTC()
+		var pattern, when, occurrence string
+		switch {
+		case float64(startsAt[t.Hour()]) >= dailyPeakShare*s.Days:
+			pattern, when, occurrence = "daily", fmt.Sprintf("%02d:00 UTC", t.Hour()), t.Format("2006-01-02")
+		case monthEnd(t):
+			pattern, when = "month-end", "last days of the month"
+			occurrence = t.AddDate(0, 0, 3).Format("2006-01") // month the run ends into
+		default:
+			pattern, when, occurrence = "weekly", fmt.Sprintf("%s %02d:00 UTC", weekdays[t.Weekday()], t.Hour()), t.Format("2006-01-02")
+		}
+		key := pattern + " " + when
+		g, ok := groups[key]
+		if !ok {
+			g = &group{peak: model.RecurringPeak{Pattern: pattern, When: when}, seen: map[string]bool{}}
+			groups[key] = g
+			keys = append(keys, key)
+		}
+		g.seen[occurrence] = true
+		g.samples = append(g.samples, e.samples...)
+		g.peak.PeakCPU = math.Max(g.peak.PeakCPU, max(e.samples))
+	}
+
+	var peakSamples [][]float64
+	for _, key := range keys {
+		g := groups[key]
+		g.peak.Occurrences = len(g.seen)
+		if g.peak.Occurrences < peakMinOccurrences {
+			continue
+		}
+		s.Peaks = append(s.Peaks, g.peak)
+		peakSamples = append(peakSamples, g.samples)
+	}
+
+	// The longest cycle decides how much history a single analysis needs
+	if s.Daily {
+		s.PeriodHours = 24
+	}
+	if s.Weekly {
+		s.PeriodHours = scheduleHoursPerWeek
+	}
+	for _, p := range s.Peaks {
+		period := map[string]int{"daily": 24, "weekly": scheduleHoursPerWeek, "month-end": hoursPerMonth}[p.Pattern]
+		s.PeriodHours = maxInt(s.PeriodHours, period)
+	}
+
+	return s, peakSamples, true
+}
+
+// monthEnd reports whether t falls on the last two days or the first day of a month
+func monthEnd(t time.Time) bool {
+	return t.Day() == 1 || t.AddDate(0, 0, 1).Day() == 1 || t.AddDate(0, 0, 2).Day() == 1
+}
+
+// cycleName describes a cycle length for reasons
+func cycleName(hours int) string {
+	switch hours {
+	case 24:
+		return "daily"
+	case scheduleHoursPerWeek:
+		return "weekly"
+	case hoursPerMonth:
+		return "monthly"
+	}
+	return fmt.Sprintf("%dh", hours)
+}
@@ -0,0 +1,104 @@
+package analyser
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// hourlyPoints returns days of hourly CPU from start, valued by cpu
+func hourlyPoints(start time.Time, days int, cpu func(time.Time) float64) []model.CPUPoint {
+	points := make([]model.CPUPoint, 0, days*24)
+	for h := 0; h < days*24; h++ {
+		t := start.Add(time.Duration(h) * time.Hour)
+		points = append(points, model.CPUPoint{Time: t, Value: cpu(t)})
+	}
+	return points
+}
+
+func TestDetectPattern(t *testing.T) {
+	jan := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	monday := time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC)
+	officeHours := func(t time.Time) bool { return t.Hour() >= 9 && t.Hour() < 17 }
+
+	tests := []struct {
+		name        string
+		points      []model.CPUPoint
+		wantOK      bool
+		daily       bool
+		weekly      bool
+		periodHours int
+		peaks       []model.RecurringPeak
+	}{
+		{
+			name:   "too little history",
+			points: hourlyPoints(jan, 1, func(time.Time) float64 { return 10 }),
+		},
+		{
+			name: "busy every working day",
+			points: hourlyPoints(jan, 14, func(t time.Time) float64 {
+				if officeHours(t) {
+					return 60
+				}
+				return 10
+			}),
+			wantOK: true, daily: true, periodHours: 24,
+		},
+		{
+			name: "quiet weekends",
+			points: hourlyPoints(monday, 28, func(t time.Time) float64 {
+				if officeHours(t) && t.Weekday() != time.Saturday && t.Weekday() != time.Sunday {
+					return 60
+				}
+				return 10
+			}),
+			wantOK: true, daily: true, weekly: true, periodHours: scheduleHoursPerWeek,
+		},
+		{
+			name: "Monday night batch",
+			points: hourlyPoints(monday, 21, func(t time.Time) float64 {
+				if t.Weekday() == time.Monday && t.Hour() == 2 {
+					return 80
+				}
+				return 10
+			}),
+			wantOK: true, weekly: true, periodHours: scheduleHoursPerWeek,
+			peaks: []model.RecurringPeak{{Pattern: "weekly", When: "MON 02:00 UTC", Occurrences: 3, PeakCPU: 80}},
+		},
+		{
+			name: "month-end close",
+			points: hourlyPoints(jan, 62, func(t time.Time) float64 {
+				if t.AddDate(0, 0, 1).Day() == 1 && t.Hour() >= 10 && t.Hour() < 13 {
+					return 95
+				}
+				return 10
+			}),
+			wantOK: true, periodHours: hoursPerMonth,
+			peaks: []model.RecurringPeak{{Pattern: "month-end", When: "last days of the month", Occurrences: 2, PeakCPU: 95}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, samples, ok := detectPattern(tt.points)
+			if ok != tt.wantOK {
+				t.Fatalf("detectPattern ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if s.Daily != tt.daily || s.Weekly != tt.weekly || s.PeriodHours != tt.periodHours {
+				t.Errorf("daily %v (ACF %.2f) weekly %v (ACF %.2f) period %dh, want daily %v weekly %v period %dh",
+					s.Daily, s.DailyACF, s.Weekly, s.WeeklyACF, s.PeriodHours, tt.daily, tt.weekly, tt.periodHours)
+			}
+			if len(s.Peaks) != len(tt.peaks) || len(samples) != len(tt.peaks) {
+				t.Fatalf("peaks = %+v, want %+v", s.Peaks, tt.peaks)
+			}
+			for i, want := range tt.peaks {
+				if s.Peaks[i] != want {
+					t.Errorf("peak %d = %+v, want %+v", i, s.Peaks[i], want)
+				}
+			}
+		})
+	}
+}
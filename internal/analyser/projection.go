@@ -0,0 +1,79 @@
+package analyser
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// projectUtilisation rescales CPU samples observed on one type to another using
+// vCPU counts and per-family performance factors from the catalog. Projected
+// samples are capped at 100%.
+func projectUtilisation(samples []float64, fromType, toType string) (model.Projection, bool) {
+	cat := catalog.Default()
+
+	from, ok := cat.Lookup(fromType)
+	if !ok || len(samples) == 0 {
+		return model.Projection{}, false
+	}
+	to, ok := cat.Lookup(toType)
+	if !ok {
+		return model.Projection{}, false
+	}
+
+	scale := (float64(from.VCPU) * cat.Performance(fromType)) / (float64(to.VCPU) * cat.Performance(toType))
+	projected := make([]float64, len(samples))
+	for i, v := range samples {
+		projected[i] = math.Min(v*scale, 100)
+	}
+
+	return model.Projection{
+		InstanceType: toType,
+		AvgCPU:       average(projected),
+		P95CPU:       percentile(projected, 95),
+		PeakCPU:      max(projected),
+	}, true
+}
+
+// checkProjection projects utilisation onto the suggested type. A suggestion is
+// rejected when its projected p95 exceeds the target and is worse than today,
+// so upsizes that still run hot are kept.
+func checkProjection(rec *model.Recommendation, samples []float64, targetCPU float64) {
+	if rec.SuggestedType == "" || rec.SuggestedType == rec.InstanceType {
+		return
+	}
+	proj, ok := projectUtilisation(samples, rec.InstanceType, rec.SuggestedType)
+	if !ok {
+		return
+	}
+
+	if targetCPU > 0 && proj.P95CPU > targetCPU && proj.P95CPU > percentile(samples, 95) {
+		rec.Reason = fmt.Sprintf("%s to %s rejected: projected p95 CPU %.1f%% exceeds the %.0f%% target.",
+			rec.Action, rec.SuggestedType, proj.P95CPU, targetCPU)
+		rec.Action = "Keep as-is"
+		rec.SuggestedType = ""
+		rec.EstimatedSaving = 0
+		return
+	}
+
+	rec.Projection = &proj
+	rec.Reason += fmt.Sprintf(" Projected on %s: avg %.1f%%, p95 %.1f%%, peak %.1f%%.",
+		proj.InstanceType, proj.AvgCPU, proj.P95CPU, proj.PeakCPU)
+}
+
+// percentile returns the p-th percentile using nearest-rank
+func percentile(xs []float64, p float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,62 @@
+package analyser
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPercentile(t *testing.T) {
+	xs := []float64{5, 1, 3, 2, 4}
+	tests := []struct {
+		xs   []float64
+		p    float64
+		want float64
+	}{
+		{nil, 95, 0},
+		{xs, 0, 1},
+		{xs, 50, 3},
+		{xs, 95, 5},
+		{xs, 100, 5},
+		{[]float64{7}, 10, 7},
+	}
+	for _, tt := range tests {
+		if got := percentile(tt.xs, tt.p); got != tt.want {
+			t.Errorf("percentile(%v, %.0f) = %.1f, want %.1f", tt.xs, tt.p, got, tt.want)
+		}
+	}
+}
+
+func TestProjectUtilisation(t *testing.T) {
+	tests := []struct {
+		name    string
+		samples []float64
+		from    string
+		to      string
+		wantOK  bool
+		avg     float64
+		p95     float64
+		peak    float64
+	}{
+		// Half the vCPUs doubles utilisation, capped at 100%
+		{"half the vCPUs", []float64{10, 20, 60}, "m5.xlarge", "m5.large", true, 160.0 / 3, 100, 100},
+		// m6i does 1.15x the work of m5 per vCPU
+		{"faster family", []float64{23, 46}, "m5.large", "m6i.large", true, 30, 40, 40},
+		{"unknown source type", []float64{10}, "x9.large", "m5.large", false, 0, 0, 0},
+		{"unknown target type", []float64{10}, "m5.large", "x9.large", false, 0, 0, 0},
+		{"no samples", nil, "m5.large", "m6i.large", false, 0, 0, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			proj, ok := projectUtilisation(tt.samples, tt.from, tt.to)
+			if ok != tt.wantOK {
+				t.Fatalf("projectUtilisation ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if proj.InstanceType != tt.to || math.Abs(proj.AvgCPU-tt.avg) > 1e-6 || math.Abs(proj.P95CPU-tt.p95) > 1e-6 || math.Abs(proj.PeakCPU-tt.peak) > 1e-6 {
+				t.Errorf("projection = %+v, want %s avg %.2f p95 %.2f peak %.2f", proj, tt.to, tt.avg, tt.p95, tt.peak)
+			}
+		})
+	}
+}
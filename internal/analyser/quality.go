@@ -0,0 +1,118 @@
+package analyser
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Metric data quality
+const (
+	gapFactor           = 1.5  // a step this many periods long or more between datapoints is a gap
+	flatLineMinPoints   = 12   // an hour of 5 minute samples
+	flatLineShare       = 0.5  // the same value for this share of samples = flat line
+	flatLineTolerance   = 0.01 // CPU percent two samples may differ by and still be the same value
+	qualityNoteCoverage = 90.0 // coverage below this is mentioned in the reason
+)
+
+// reviewActions are already a call for a human to look; low coverage cannot
+// downgrade them further
+var reviewActions = map[string]bool{
+	"Keep as-is": true, "Unknown": true, "Review": true, "Review / Potentially Stop": true,
+}
+
+// assessQuality compares the datapoints received with those the period and
+// window should have produced, and finds gaps between datapoints and flat
+// lines that suggest a stuck metric.
+func assessQuality(series model.CPUSampleSeries, hours int) model.DataQuality {
+	q := model.DataQuality{ReceivedPoints: len(series.Samples)}
+	if series.PeriodSeconds <= 0 {
+		return q
+	}
+	window := series.WindowSeconds
+	if window <= 0 {
+		window = hours * 3600
+	}
+	q.ExpectedPoints = window / series.PeriodSeconds
+	if q.ExpectedPoints > 0 {
+		q.CoveragePct = math.Min(100, float64(q.ReceivedPoints)/float64(q.ExpectedPoints)*100)
+	}
+
+	times := append([]time.Time(nil), series.Timestamps...)
+	sort.Slice(times, func(i, j int) bool { return times[i].Before(times[j]) })
+	period := time.Duration(series.PeriodSeconds) * time.Second
+	for i := 1; i < len(times); i++ {
+		step := times[i].Sub(times[i-1])
+		if float64(step) < gapFactor*float64(period) {
+			continue
+		}
+		q.Gaps++
+		q.LongestGapMinutes = maxInt(q.LongestGapMinutes, int((step - period).Minutes()))
+	}
+
+	run, longest := 1, 0
+	for i := 1; i <= len(series.Samples); i++ {
+		if i < len(series.Samples) && math.Abs(series.Samples[i]-series.Samples[i-run]) <= flatLineTolerance {
+			run++
+			continue
+		}
+		if run > longest {
+			longest = run
+			q.FlatLineValue = series.Samples[i-run]
+		}
+		run = 1
+	}
+	q.FlatLine = longest >= flatLineMinPoints && float64(longest) >= flatLineShare*float64(len(series.Samples))
+	if !q.FlatLine {
+		q.FlatLineValue = 0
+	}
+	return q
+}
+
+// applyQuality attaches data quality to a recommendation, notes gaps and flat
+// lines in the reason, and holds actions for review when coverage is below
+// minCoverage percent
+func applyQuality(rec *model.Recommendation, q model.DataQuality, minCoverage float64) {
+	rec.DataQuality = &q
+	if q.ExpectedPoints == 0 || q.ReceivedPoints == 0 {
+		return
+	}
+
+	note := ""
+	if q.CoveragePct < qualityNoteCoverage || q.Gaps > 0 {
+		note = fmt.Sprintf(" CPU coverage %.0f%% (%d of %d datapoints", q.CoveragePct, q.ReceivedPoints, q.ExpectedPoints)
+		if q.Gaps > 0 {
+			note += fmt.Sprintf(", %d gap(s), longest %s", q.Gaps, gapLabel(q.LongestGapMinutes))
+		}
+		note += ")."
+	}
+	if q.FlatLine {
+		note += fmt.Sprintf(" CPU sat at %.1f%% for most of the window; check the metric is still being published.", q.FlatLineValue)
+	}
+
+	if minCoverage <= 0 || q.CoveragePct >= minCoverage || reviewActions[rec.Action] {
+		rec.Reason += note
+		return
+	}
+	held := rec.Action
+	if rec.SuggestedType != "" && rec.SuggestedType != rec.InstanceType {
+		held += " to " + rec.SuggestedType
+	}
+	rec.Reason = fmt.Sprintf("%s held for review: only %.0f%% of expected CPU datapoints arrived (%d of %d), below the %.0f%% minimum. %s%s",
+		held, q.CoveragePct, q.ReceivedPoints, q.ExpectedPoints, minCoverage, rec.Reason, note)
+	rec.Action = "Review"
+	rec.SuggestedType = ""
+	rec.EstimatedSaving = 0
+	rec.Projection = nil
+}
+
+// gapLabel formats a gap length in minutes or hours
+func gapLabel(minutes int) string {
+	if minutes < 60 {
+		return fmt.Sprintf("%dm", minutes)
+	}
+	return fmt.Sprintf("%.1fh", float64(minutes)/60)
+}
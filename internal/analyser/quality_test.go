@@ -0,0 +1,99 @@
+package analyser
+
+import (
+	"math"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// fiveMinuteSeries returns samples taken at the given five minute slots
+func fiveMinuteSeries(slots []int, value func(i int) float64) model.CPUSampleSeries {
+	start := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	series := model.CPUSampleSeries{PeriodSeconds: 300}
+	for i, slot := range slots {
+		series.Samples = append(series.Samples, value(i))
+		series.Timestamps = append(series.Timestamps, start.Add(time.Duration(slot)*5*time.Minute))
+	}
+	return series
+}
+
+// slots returns the five minute slots from 0 to n-1, skipping any listed
+func slots(n int, skip ...int) []int {
+	var out []int
+	for i := 0; i < n; i++ {
+		if !slices.Contains(skip, i) {
+			out = append(out, i)
+		}
+	}
+	return out
+}
+
+func TestAssessQuality(t *testing.T) {
+	varied := func(i int) float64 { return float64(i) }
+	windowed := fiveMinuteSeries(slots(12), varied)
+	windowed.WindowSeconds = 7200
+
+	tests := []struct {
+		name   string
+		series model.CPUSampleSeries
+		hours  int
+		want   model.DataQuality
+	}{
+		{
+			name:   "complete hour",
+			series: fiveMinuteSeries(slots(12), varied),
+			hours:  1,
+			want:   model.DataQuality{ExpectedPoints: 12, ReceivedPoints: 12, CoveragePct: 100},
+		},
+		{
+			name:   "three missing datapoints make one gap",
+			series: fiveMinuteSeries(slots(12, 5, 6, 7), varied),
+			hours:  1,
+			want:   model.DataQuality{ExpectedPoints: 12, ReceivedPoints: 9, CoveragePct: 75, Gaps: 1, LongestGapMinutes: 15},
+		},
+		{
+			name:   "query window overrides hours",
+			series: windowed,
+			hours:  1,
+			want:   model.DataQuality{ExpectedPoints: 24, ReceivedPoints: 12, CoveragePct: 50},
+		},
+		{
+			name:   "stuck metric",
+			series: fiveMinuteSeries(slots(24), func(int) float64 { return 3 }),
+			hours:  2,
+			want:   model.DataQuality{ExpectedPoints: 24, ReceivedPoints: 24, CoveragePct: 100, FlatLine: true, FlatLineValue: 3},
+		},
+		{
+			name: "short flat stretch",
+			series: fiveMinuteSeries(slots(24), func(i int) float64 {
+				if i < 6 {
+					return 3
+				}
+				return float64(i)
+			}),
+			hours: 2,
+			want:  model.DataQuality{ExpectedPoints: 24, ReceivedPoints: 24, CoveragePct: 100},
+		},
+		{
+			name:   "unknown period",
+			series: model.CPUSampleSeries{Samples: []float64{1, 2}},
+			hours:  1,
+			want:   model.DataQuality{ReceivedPoints: 2},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := assessQuality(tt.series, tt.hours)
+			if math.Abs(got.CoveragePct-tt.want.CoveragePct) > 1e-6 {
+				t.Errorf("coverage = %.2f%%, want %.2f%%", got.CoveragePct, tt.want.CoveragePct)
+			}
+			got.CoveragePct = tt.want.CoveragePct
+			if got != tt.want {
+				t.Errorf("assessQuality = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package analyser
+
+import (
+	"fmt"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// minRightsizeGain is the price reduction a new type must offer to be worth a change
+const minRightsizeGain = 0.05
+
+// sizingNeed is the capacity an instance must provide, including headroom
+type sizingNeed struct {
+	vcpu        float64
+	memoryGB    float64
+	networkGbps float64
+}
+
+// rightsizeResult is the cheapest catalog type that satisfies the observed need
+type rightsizeResult struct {
+	target catalog.InstanceType
+	saving float64
+	reason string
+}
+
+// rightsize searches the whole catalog, not just the current family, for the
+// cheapest type that covers observed CPU, memory and network throughput
+// peaks with headroom. Candidates keep the current architecture and
+// burstable model, and previous-generation families are skipped. Burstable
+// candidates must also keep a positive credit balance when the CPU series is
+// replayed on them, as in burstableSwitch.
+func rightsize(
+	inst model.EC2Instance,
+	cpu model.CPUSampleSeries,
+	peakMem float64,
+	net model.NetworkActivity,
+	opts Options,
+	monthlyCost float64,
+) (rightsizeResult, bool) {
+	cat := catalog.Default()
+
+	current, ok := cat.Lookup(inst.InstanceType)
+	if !ok {
+		return rightsizeResult{}, false
+	}
+	currentFamily := cat.Families[current.Family]
+
+	h := 1 + opts.HeadroomPct/100
+	need := sizingNeed{
+		vcpu:     float64(current.VCPU) * max(cpu.Samples) / 100 * h,
+		memoryGB: current.MemoryGB * peakMem / 100 * h,
+	}
+	need.networkGbps = net.PeakBytesPerSec * 8 / 1e9 * h
+
+	var best, bestSameFamily, drained *catalog.InstanceType
+	for _, t := range cat.Types {
+		f := cat.Families[t.Family]
+		if f.Architecture != currentFamily.Architecture || f.Burstable != currentFamily.Burstable || f.PreviousGeneration {
+			continue
+		}
+		if float64(t.VCPU) < need.vcpu || t.MemoryGB < need.memoryGB || t.NetworkGbps < need.networkGbps {
+			continue
+		}
+		if f.Burstable {
+			if len(cpu.Samples) == 0 || cpu.PeriodSeconds <= 0 {
+				continue
+			}
+			if _, ok := simulateCredits(t, cpu, current.VCPU); !ok {
+				if t.Name != current.Name && (drained == nil || cheaper(t, *drained)) {
+					drained = &t
+				}
+				continue
+			}
+		}
+		if best == nil || cheaper(t, *best) {
+			best = &t
+		}
+		if t.Family == current.Family && (bestSameFamily == nil || cheaper(t, *bestSameFamily)) {
+			bestSameFamily = &t
+		}
+	}
+
+	if best == nil || best.Name == current.Name || best.HourlyPrice > current.HourlyPrice*(1-minRightsizeGain) {
+		return rightsizeResult{}, false
+	}
+
+	ratio := 1 - best.HourlyPrice/current.HourlyPrice
+	res := rightsizeResult{
+		target: *best,
+		saving: monthlyCost * ratio,
+	}
+
+	needs := fmt.Sprintf("needs %.1f vCPU, %.1f GiB memory and %.2f Gbps with %.0f%% headroom",
+		need.vcpu, need.memoryGB, need.networkGbps, opts.HeadroomPct)
+	switch {
+	case best.Family == current.Family:
+		res.reason = fmt.Sprintf("%s; %s is the cheapest fit (%.0f%% cheaper).", needs, best.Name, ratio*100)
+	case bestSameFamily == nil || bestSameFamily.Name == current.Name:
+		res.reason = fmt.Sprintf("%s; no smaller %s size fits, %s ($%.4f/h) does at %.0f%% lower cost.",
+			needs, current.Family, best.Name, best.HourlyPrice, ratio*100)
+	default:
+		res.reason = fmt.Sprintf("%s; %s ($%.4f/h) beat same-family %s ($%.4f/h).",
+			needs, best.Name, best.HourlyPrice, bestSameFamily.Name, bestSameFamily.HourlyPrice)
+	}
+	if drained != nil && cheaper(*drained, *best) {
+		res.reason += fmt.Sprintf(" %s is cheaper but would run out of CPU credits.", drained.Name)
+	}
+
+	return res, true
+}
+
+// cheaper orders candidates by price, then more network bandwidth, then name
+func cheaper(a, b catalog.InstanceType) bool {
+	if a.HourlyPrice != b.HourlyPrice {
+		return a.HourlyPrice < b.HourlyPrice
+	}
+	if a.NetworkGbps != b.NetworkGbps {
+		return a.NetworkGbps > b.NetworkGbps
+	}
+	return a.Name < b.Name
+}
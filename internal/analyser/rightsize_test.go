@@ -0,0 +1,57 @@
+package analyser
+
+import (
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// alternate returns n samples alternating between a and b
+func alternate(a, b float64, n int) []float64 {
+	xs := make([]float64, n)
+	for i := range xs {
+		xs[i] = a
+		if i%2 == 1 {
+			xs[i] = b
+		}
+	}
+	return xs
+}
+
+func TestRightsize(t *testing.T) {
+	opts := Options{MetricHours: 24, HeadroomPct: 20}
+
+	tests := []struct {
+		name    string
+		current string
+		cpu     []float64
+		peakMem float64
+		net     model.NetworkActivity
+		want    string // "" = no change
+	}{
+		{"cheapest fit across families", "r5.xlarge", []float64{20}, 10, model.NetworkActivity{}, "c6i.large"},
+		{"memory rules out compute types", "r5.xlarge", []float64{20}, 40, model.NetworkActivity{}, "r6i.large"},
+		// 11 Gbps at peak is 13.2 with headroom, more than any candidate offers,
+		// even though the daily total averages out to almost nothing
+		{"network peak blocks the change", "r5.xlarge", []float64{20}, 10, model.NetworkActivity{NetworkInBytes: 1e9, PeakBytesPerSec: 11e9 / 8}, ""},
+		{"peak needs the whole instance", "r5.xlarge", []float64{90}, 10, model.NetworkActivity{}, ""},
+		{"unknown type", "x9.large", []float64{10}, 10, model.NetworkActivity{}, ""},
+		{"steady load within a smaller burstable baseline", "t3.micro", repeat(2, 288), 10, model.NetworkActivity{}, "t3.nano"},
+		// Averages 4.7% against t3.nano's 5% baseline, but the 8% bursts spend
+		// credits faster than they are earned from an empty balance
+		{"bursts would drain a smaller burstable", "t3.micro", alternate(8, 1.4, 288), 10, model.NetworkActivity{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cpu := model.CPUSampleSeries{Samples: tt.cpu, PeriodSeconds: 300}
+			res, ok := rightsize(model.EC2Instance{InstanceType: tt.current}, cpu, tt.peakMem, tt.net, opts, 100)
+			got := ""
+			if ok {
+				got = res.target.Name
+			}
+			if got != tt.want {
+				t.Errorf("rightsize(%s) = %q, want %q (%s)", tt.current, got, tt.want, res.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,212 @@
+package analyser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Off-hours schedule detection
+const (
+	scheduleActiveCPU     = 5.0 // hourly average CPU at or above this = in use
+	scheduleMinDays       = 7   // history needed to see every weekday
+	scheduleMinCoverage   = 0.8 // share of expected hourly points that must be present
+	schedulePadHours      = 1   // warm-up before and grace after observed use
+	scheduleMaxOffActive  = 5.0 // percent of off-window hours allowed to show use
+	scheduleMinOffHours   = 40  // hours per week stopped for a schedule to be worth it
+	scheduleHoursPerWeek  = 7 * 24
+	scheduleMaxOffHourCPU = 10.0 // mean CPU outside the window must stay below this
+)
+
+// weekdays are the day names used in schedules, indexed by time.Weekday
+var weekdays = [7]string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}
+
+// weekOrder lists days Monday first, the order schedules are written in
+var weekOrder = []time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday, time.Friday, time.Saturday, time.Sunday}
+
+// ScheduleOptions controls off-hours schedule detection.
+type ScheduleOptions struct {
+	Days     int            // days of hourly CPU history to analyse
+	CostDays int            // cost window passed to Cost Explorer
+	Location *time.Location // timezone the schedule is expressed in
+}
+
+// AdviseSchedules looks for clear business-hours usage on running non-prod
+// instances (by Environment tag) and proposes a start/stop window with its
+// savings. Instances without a clear pattern are returned unsuitable, with
+// the reason.
+func AdviseSchedules(
+	ctx context.Context,
+	instances []model.EC2Instance,
+	cw awsclient.CloudWatchClient,
+	ce awsclient.CostExplorerClient,
+	opts ScheduleOptions,
+) []model.ScheduleAdvice {
+	if opts.Location == nil {
+		opts.Location = time.UTC
+	}
+
+	var out []model.ScheduleAdvice
+	for _, inst := range instances {
+		env := strings.ToLower(tagValue(inst.Tags, "Environment"))
+		if inst.State != "running" || !nonProdEnvironments[env] {
+			continue
+		}
+
+		advice := model.ScheduleAdvice{
+			InstanceID:       inst.ID,
+			InstanceType:     inst.InstanceType,
+			Environment:      env,
+			AutoScalingGroup: inst.AutoScalingGroup,
+			Timezone:         opts.Location.String(),
+		}
+
+		history, err := cw.GetHourlyCpu(ctx, inst.ID, opts.Days)
+		if err != nil {
+			logging.DebugErr("Hourly CPU unavailable for "+inst.ID, err)
+			advice.Reason = fmt.Sprintf("Failed to load hourly CPU: %v", err)
+			out = append(out, advice)
+			continue
+		}
+
+		advice.MonthlyCost = fetchCost(ctx, ce, inst.ID, opts.CostDays).MonthlyCost
+		if advice.MonthlyCost == 0 {
+			if t, ok := catalog.Default().Lookup(inst.InstanceType); ok {
+				advice.MonthlyCost = t.HourlyPrice * hoursPerMonth
+			}
+		}
+
+		detectSchedule(&advice, history.Points, opts)
+		out = append(out, advice)
+	}
+	return out
+}
+
+// detectSchedule fills in the window, activity and savings from hourly CPU
+func detectSchedule(a *model.ScheduleAdvice, points []model.CPUPoint, opts ScheduleOptions) {
+	if len(points) == 0 {
+		a.Reason = "No hourly CPU data available."
+		return
+	}
+	span := points[len(points)-1].Time.Sub(points[0].Time).Hours()/24 + 1.0/24
+	if span < scheduleMinDays || float64(len(points)) < span*24*scheduleMinCoverage {
+		a.Reason = fmt.Sprintf("Only %d hourly CPU points over %.0f days; need %d days to see every weekday.",
+			len(points), span, scheduleMinDays)
+		return
+	}
+
+	// Mean CPU per day of week and hour of day, in the schedule's timezone
+	var total, count [7][24]float64
+	for _, p := range points {
+		t := p.Time.In(opts.Location)
+		total[t.Weekday()][t.Hour()] += p.Value
+		count[t.Weekday()][t.Hour()]++
+	}
+
+	start, stop := 24, 0
+	var active [7]bool
+	for d := 0; d < 7; d++ {
+		for h := 0; h < 24; h++ {
+			if count[d][h] == 0 || total[d][h]/count[d][h] < scheduleActiveCPU {
+				continue
+			}
+			active[d] = true
+			start = min(start, h)
+			stop = maxInt(stop, h+1)
+		}
+	}
+	if start > stop {
+		a.Reason = fmt.Sprintf("CPU never averages %.0f%% in any hour; review whether the instance is needed at all.", scheduleActiveCPU)
+		return
+	}
+	start = maxInt(0, start-schedulePadHours)
+	stop = min(24, stop+schedulePadHours)
+
+	for _, d := range weekOrder {
+		if active[d] {
+			a.Days = append(a.Days, weekdays[d])
+		}
+	}
+	a.StartHour, a.StopHour = start, stop
+	a.RunningHoursPerWeek = len(a.Days) * (stop - start)
+
+	// How much use falls outside the window
+	var inSum, inN, offSum, offN, offActive float64
+	for _, p := range points {
+		t := p.Time.In(opts.Location)
+		if active[t.Weekday()] && t.Hour() >= start && t.Hour() < stop {
+			inSum += p.Value
+			inN++
+			continue
+		}
+		offSum += p.Value
+		offN++
+		if p.Value >= scheduleActiveCPU {
+			offActive++
+		}
+	}
+	if inN > 0 {
+		a.BusinessHoursCPU = inSum / inN
+	}
+	if offN > 0 {
+		a.OffHoursCPU = offSum / offN
+		a.OffHoursActivePct = offActive / offN * 100
+	}
+
+	window := fmt.Sprintf("%s %02d:00-%02d:00 %s", DescribeDays(a.Days), start, stop, a.Timezone)
+	offHours := scheduleHoursPerWeek - a.RunningHoursPerWeek
+	switch {
+	case offHours < scheduleMinOffHours:
+		a.Reason = fmt.Sprintf("In use across most of the week (%s); only %d hours a week could be stopped.", window, offHours)
+	case a.OffHoursActivePct > scheduleMaxOffActive || a.OffHoursCPU >= scheduleMaxOffHourCPU:
+		a.Reason = fmt.Sprintf("Use outside %s in %.0f%% of off hours (mean CPU %.1f%%); no clear business-hours pattern.",
+			window, a.OffHoursActivePct, a.OffHoursCPU)
+	default:
+		a.Suitable = true
+		a.MonthlySaving = a.MonthlyCost * float64(offHours) / scheduleHoursPerWeek
+		a.Reason = fmt.Sprintf("Used %s (mean CPU %.1f%%, %.1f%% outside); stopping the other %d hours a week saves $%.2f/mo.",
+			window, a.BusinessHoursCPU, a.OffHoursCPU, offHours, a.MonthlySaving)
+		if a.AutoScalingGroup != "" {
+			a.Reason += " It is in Auto Scaling group " + a.AutoScalingGroup + ", so schedule the group's capacity rather than the instance."
+		}
+	}
+}
+
+// DescribeDays collapses consecutive days into ranges, e.g. MON-FRI
+func DescribeDays(days []string) string {
+	if len(days) == 0 {
+		return "-"
+	}
+	index := map[string]int{}
+	for i, d := range weekOrder {
+		index[weekdays[d]] = i
+	}
+	var parts []string
+	for i := 0; i < len(days); {
+		j := i
+		for j+1 < len(days) && index[days[j+1]] == index[days[j]]+1 {
+			j++
+		}
+		if j > i {
+			parts = append(parts, days[i]+"-"+days[j])
+		} else {
+			parts = append(parts, days[i])
+		}
+		i = j + 1
+	}
+	return strings.Join(parts, ",")
+}
+
+// maxInt returns the larger of two ints (max is taken by the float helper)
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,134 @@
+package analyser
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Risk levels for simulations
+const (
+	RiskLow    = "low"
+	RiskMedium = "medium"
+	RiskHigh   = "high"
+)
+
+// Simulate answers "what if this instance were targetType?" using the same
+// metric and cost fetch as AnalyseInstances. Utilisation is projected with the
+// catalog, memory fit is checked against observed peaks, and a risk level is
+// derived from opts.TargetCPU and opts.HeadroomPct.
+func Simulate(
+	ctx context.Context,
+	inst model.EC2Instance,
+	targetType string,
+	cw awsclient.CloudWatchClient,
+	ce awsclient.CostExplorerClient,
+	opts Options,
+) (model.Simulation, error) {
+	cat := catalog.Default()
+
+	current, ok := cat.Lookup(inst.InstanceType)
+	if !ok {
+		return model.Simulation{}, fmt.Errorf("current type %s is not in the instance catalog", inst.InstanceType)
+	}
+	target, ok := cat.Lookup(targetType)
+	if !ok {
+		return model.Simulation{}, fmt.Errorf("target type %s is not in the instance catalog", targetType)
+	}
+
+	cpuSeries, err := cw.GetCpuUtilisation(ctx, inst.ID, opts.MetricHours)
+	if err != nil {
+		return model.Simulation{}, fmt.Errorf("failed to load CPU metrics: %w", err)
+	}
+	cost := fetchCost(ctx, ce, inst.ID, opts.CostDays)
+
+	sim := model.Simulation{
+		InstanceID:  inst.ID,
+		CurrentType: inst.InstanceType,
+		TargetType:  targetType,
+		MonthlyCost: cost.MonthlyCost,
+		Risk:        RiskLow,
+	}
+
+	// Cost: scale what is actually billed by the catalog price ratio
+	if cost.MonthlyCost > 0 && current.HourlyPrice > 0 {
+		sim.ProjectedMonthlyCost = cost.MonthlyCost * target.HourlyPrice / current.HourlyPrice
+	} else {
+		sim.MonthlyCost = current.HourlyPrice * hoursPerMonth
+		sim.ProjectedMonthlyCost = target.HourlyPrice * hoursPerMonth
+		sim.Notes = append(sim.Notes, "no billed cost available; using catalog prices")
+	}
+	sim.MonthlyDelta = sim.ProjectedMonthlyCost - sim.MonthlyCost
+
+	raise := func(level, note string) {
+		if level == RiskHigh || sim.Risk == RiskLow {
+			sim.Risk = level
+		}
+		sim.Notes = append(sim.Notes, note)
+	}
+
+	// CPU
+	if len(cpuSeries.Samples) == 0 {
+		raise(RiskHigh, "no CPU samples to project from")
+	} else {
+		sim.CurrentCPU = &model.Projection{
+			InstanceType: inst.InstanceType,
+			AvgCPU:       average(cpuSeries.Samples),
+			P95CPU:       percentile(cpuSeries.Samples, 95),
+			PeakCPU:      max(cpuSeries.Samples),
+		}
+		proj, _ := projectUtilisation(cpuSeries.Samples, inst.InstanceType, targetType)
+		sim.ProjectedCPU = &proj
+
+		switch {
+		case opts.TargetCPU > 0 && proj.P95CPU > opts.TargetCPU:
+			raise(RiskHigh, fmt.Sprintf("projected p95 CPU %.1f%% exceeds the %.0f%% target", proj.P95CPU, opts.TargetCPU))
+		case opts.TargetCPU > 0 && proj.PeakCPU > opts.TargetCPU:
+			raise(RiskMedium, fmt.Sprintf("projected peak CPU %.1f%% exceeds the %.0f%% target", proj.PeakCPU, opts.TargetCPU))
+		}
+
+		if target.BaselinePct > 0 {
+			if _, ok := simulateCredits(target, cpuSeries, current.VCPU); !ok {
+				raise(RiskHigh, fmt.Sprintf("%s would run out of CPU credits on the observed load", targetType))
+			}
+		}
+	}
+
+	// Memory
+	if peakMem, ok := fetchPeakMemory(ctx, cw, inst.ID, opts.MetricHours); ok {
+		sim.PeakMemoryPercent = peakMem
+		sim.ProjectedMemory = peakMem * current.MemoryGB / target.MemoryGB
+		fits := sim.ProjectedMemory <= 100
+		sim.MemoryFits = &fits
+
+		limit := 100 / (1 + opts.HeadroomPct/100)
+		switch {
+		case !fits:
+			raise(RiskHigh, fmt.Sprintf("peak memory would need %.0f%% of %.1f GiB", sim.ProjectedMemory, target.MemoryGB))
+		case sim.ProjectedMemory > limit:
+			raise(RiskMedium, fmt.Sprintf("projected memory %.0f%% leaves less than %.0f%% headroom", sim.ProjectedMemory, opts.HeadroomPct))
+		}
+	} else if target.MemoryGB < current.MemoryGB {
+		raise(RiskMedium, fmt.Sprintf("no memory metrics; %s has %.1f GiB vs %.1f GiB today", targetType, target.MemoryGB, current.MemoryGB))
+	}
+
+	// Architecture
+	fromArch := cat.Families[current.Family].Architecture
+	toArch := cat.Families[target.Family].Architecture
+	if fromArch != toArch {
+		switch {
+		case strings.Contains(strings.ToLower(inst.Platform), "windows"):
+			raise(RiskHigh, "Windows has no arm64 AMIs on EC2")
+		case toArch == "arm64" && cat.IsX86OnlyImage(inst.ImageName):
+			raise(RiskHigh, fmt.Sprintf("AMI %s is x86-only", inst.ImageName))
+		default:
+			raise(RiskMedium, fmt.Sprintf("architecture changes from %s to %s; the AMI must be rebuilt", fromArch, toArch))
+		}
+	}
+
+	return sim, nil
+}
@@ -0,0 +1,290 @@
+package analyser
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Spot suitability scoring
+const (
+	spotBaseScore      = 50
+	spotMinScore       = 60   // suitable at or above this score
+	spotUptimeDays     = 14   // days of hourly CPU used to measure uptime
+	spotPartTime       = 0.8  // up for less than this share of hours = already stopped and started
+	spotAlwaysOn       = 0.95 // up for at least this share outside an ASG = long-lived server
+	spotMinPools       = 3    // interchangeable types needed for a diversified fleet
+	spotHighDiscount   = 0.6  // spot/on-demand price ratio above this signals capacity pressure
+	spotVolatileCV     = 0.05 // price coefficient of variation above this = medium volatility
+	spotVeryVolatileCV = 0.15
+)
+
+// spotProducts maps instance platforms to spot price product descriptions.
+// Platforms without spot pricing of their own (e.g. SQL Server licence
+// included) are not in the map.
+var spotProducts = map[string]string{
+	"":                         "Linux/UNIX",
+	"linux/unix":               "Linux/UNIX",
+	"red hat enterprise linux": "Red Hat Enterprise Linux",
+	"suse linux":               "SUSE Linux",
+	"windows":                  "Windows",
+}
+
+// nonProdEnvironments are Environment tag values that tolerate interruption
+var nonProdEnvironments = map[string]bool{
+	"dev": true, "development": true, "test": true, "qa": true, "staging": true, "sandbox": true,
+}
+
+// spotSkipActions are recommendations where running on spot is moot
+var spotSkipActions = map[string]bool{
+	"Stop": true, "Terminate": true, "Snapshot & Terminate": true,
+	"Review": true, "Review / Potentially Stop": true, "Unknown": true,
+}
+
+// AdviseSpot scores each running recommendation for Spot suitability using
+// tags, uptime from hourly CPU history and type diversity, prices it from
+// spot price history for the instance's platform and attaches the advice to
+// the recommendation. Advice applies to the suggested
+// type when there is one, so savings stack on top of the recommendation.
+func AdviseSpot(
+	ctx context.Context,
+	recs []model.Recommendation,
+	instances []model.EC2Instance,
+	cw awsclient.CloudWatchClient,
+	sp awsclient.SpotPricingClient,
+	days int,
+) {
+	byID := map[string]model.EC2Instance{}
+	for _, inst := range instances {
+		byID[inst.ID] = inst
+	}
+
+	for i := range recs {
+		rec := &recs[i]
+		inst, ok := byID[rec.InstanceID]
+		if !ok || rec.State != "running" || inst.Lifecycle == "spot" || spotSkipActions[rec.Action] {
+			continue
+		}
+
+		advice, ok := adviseSpot(ctx, inst, *rec, cw, sp, days)
+		if !ok {
+			continue
+		}
+		rec.Spot = &advice
+		rec.Reason += " " + describeSpot(advice)
+	}
+}
+
+// adviseSpot builds the advice for one instance; false when the type is not
+// in the catalog or has no spot price history for the instance's platform
+func adviseSpot(
+	ctx context.Context,
+	inst model.EC2Instance,
+	rec model.Recommendation,
+	cw awsclient.CloudWatchClient,
+	sp awsclient.SpotPricingClient,
+	days int,
+) (model.SpotAdvice, bool) {
+	cat := catalog.Default()
+
+	typeName := rec.InstanceType
+	baseCost := rec.MonthlyCost
+	if rec.SuggestedType != "" && rec.SuggestedType != rec.InstanceType && rec.Action != "Keep as-is" {
+		typeName = rec.SuggestedType
+		baseCost = rec.MonthlyCost - rec.EstimatedSaving
+	}
+
+	t, ok := cat.Lookup(typeName)
+	if !ok || t.HourlyPrice == 0 {
+		return model.SpotAdvice{}, false
+	}
+	product, ok := spotProducts[strings.ToLower(inst.Platform)]
+	if !ok {
+		logging.Debug(fmt.Sprintf("No spot pricing for %s on %s", inst.ID, inst.Platform))
+		return model.SpotAdvice{}, false
+	}
+
+	// Spot prices include the platform's licence, so compare them with the
+	// on-demand price on the same platform: billed cost when there is some,
+	// scaled to the suggested type, otherwise the catalog price with its
+	// licence uplift
+	onDemand := cat.OnDemandPrice(t, product)
+	if rec.HourlyCost > 0 && rec.MonthlyCost > 0 && baseCost > 0 {
+		onDemand = rec.HourlyCost * baseCost / rec.MonthlyCost
+	}
+	if baseCost <= 0 {
+		baseCost = onDemand * hoursPerMonth
+	}
+	history, err := sp.GetSpotPriceHistory(ctx, typeName, product, days)
+	if err != nil {
+		logging.DebugErr("Spot price history unavailable for "+typeName, err)
+		return model.SpotAdvice{}, false
+	}
+	prices := spotPricesIn(history, inst.AvailabilityZone)
+	if len(prices) == 0 {
+		return model.SpotAdvice{}, false
+	}
+
+	mean := average(prices)
+	ratio := mean / onDemand
+	advice := model.SpotAdvice{
+		InstanceType:    typeName,
+		SpotHourlyPrice: mean,
+		DiscountPercent: (1 - ratio) * 100,
+		ExpectedSaving:  math.Max(0, baseCost*(1-ratio)),
+		Pools:           spotPools(cat, t),
+	}
+
+	// Workload signals
+	score := spotBaseScore
+	if inst.AutoScalingGroup != "" {
+		score += 25
+		advice.Factors = append(advice.Factors, "in Auto Scaling group "+inst.AutoScalingGroup)
+	}
+	switch env := strings.ToLower(tagValue(inst.Tags, "Environment")); {
+	case nonProdEnvironments[env]:
+		score += 15
+		advice.Factors = append(advice.Factors, "Environment="+env)
+	case env == "prod" || env == "production":
+		score -= 15
+		advice.Factors = append(advice.Factors, "Environment="+env)
+	}
+	if cpu, err := cw.GetHourlyCpu(ctx, inst.ID, spotUptimeDays); err != nil {
+		logging.DebugErr("Hourly CPU unavailable for "+inst.ID, err)
+	} else if up, ok := uptimeShare(cpu.Points); ok {
+		switch {
+		case up < spotPartTime:
+			score += 10
+			advice.Factors = append(advice.Factors, fmt.Sprintf("up %.0f%% of hours", up*100))
+		case up >= spotAlwaysOn && inst.AutoScalingGroup == "":
+			score -= 15
+			advice.Factors = append(advice.Factors, fmt.Sprintf("up %.0f%% of hours outside an ASG", up*100))
+		}
+	}
+	if advice.Pools >= spotMinPools {
+		score += 10
+	} else {
+		score -= 10
+	}
+	advice.Factors = append(advice.Factors, fmt.Sprintf("%d interchangeable type(s)", advice.Pools))
+	advice.Score = min(100, score)
+	if advice.Score < 0 {
+		advice.Score = 0
+	}
+
+	// Interruption risk from price pressure and diversification
+	pressure := 0
+	switch cv := stddev(prices) / mean; {
+	case cv > spotVeryVolatileCV:
+		pressure += 2
+	case cv > spotVolatileCV:
+		pressure++
+	}
+	if ratio > spotHighDiscount {
+		pressure++
+	}
+	if advice.Pools < spotMinPools {
+		pressure++
+	}
+	switch {
+	case pressure >= 3:
+		advice.InterruptionRisk = RiskHigh
+	case pressure == 2:
+		advice.InterruptionRisk = RiskMedium
+	default:
+		advice.InterruptionRisk = RiskLow
+	}
+
+	advice.Suitable = advice.Score >= spotMinScore && advice.InterruptionRisk != RiskHigh
+	return advice, true
+}
+
+// uptimeShare returns the share of hours with a CPU datapoint between the
+// first and last datapoint. An instance stopped overnight or between batch
+// runs reports no CPU while stopped. False with less than a day of history.
+func uptimeShare(points []model.CPUPoint) (float64, bool) {
+	if len(points) < 24 {
+		return 0, false
+	}
+	first, last := points[0].Time, points[0].Time
+	for _, p := range points {
+		if p.Time.Before(first) {
+			first = p.Time
+		}
+		if p.Time.After(last) {
+			last = p.Time
+		}
+	}
+	hours := last.Sub(first).Hours() + 1
+	return math.Min(1, float64(len(points))/hours), true
+}
+
+// describeSpot summarises spot advice for the recommendation reason
+func describeSpot(a model.SpotAdvice) string {
+	if !a.Suitable {
+		return fmt.Sprintf("Spot: score %d/100, %s interruption risk; not a candidate.", a.Score, a.InterruptionRisk)
+	}
+	return fmt.Sprintf("Spot: score %d/100, %s interruption risk; %s on spot saves a further $%.2f/mo (%.0f%% off on-demand).",
+		a.Score, a.InterruptionRisk, a.InstanceType, a.ExpectedSaving, a.DiscountPercent)
+}
+
+// spotPricesIn returns prices from the instance's AZ, or every AZ when it has none
+func spotPricesIn(history []model.SpotPrice, az string) []float64 {
+	var all, local []float64
+	for _, p := range history {
+		all = append(all, p.Price)
+		if p.AvailabilityZone == az {
+			local = append(local, p.Price)
+		}
+	}
+	if len(local) > 0 {
+		return local
+	}
+	return all
+}
+
+// spotPools counts catalog types a mixed-instances fleet could use in place of t:
+// same architecture and credit model, same vCPUs, and between 1x and 2x the memory
+func spotPools(cat *catalog.Catalog, t catalog.InstanceType) int {
+	fam := cat.Families[t.Family]
+	n := 0
+	for _, c := range cat.Types {
+		cf := cat.Families[c.Family]
+		if cf.Architecture != fam.Architecture || cf.Burstable != fam.Burstable {
+			continue
+		}
+		if c.VCPU == t.VCPU && c.MemoryGB >= t.MemoryGB && c.MemoryGB <= 2*t.MemoryGB {
+			n++
+		}
+	}
+	return n
+}
+
+// tagValue looks up a tag key case-insensitively
+func tagValue(tags map[string]string, key string) string {
+	for k, v := range tags {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return ""
+}
+
+// stddev returns the population standard deviation
+func stddev(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	m := average(xs)
+	sum := 0.0
+	for _, v := range xs {
+		sum += (v - m) * (v - m)
+	}
+	return math.Sqrt(sum / float64(len(xs)))
+}
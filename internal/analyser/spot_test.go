@@ -0,0 +1,90 @@
+package analyser
+
+import (
+	"context"
+	"math"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func TestAdviseSpotPlatformPrice(t *testing.T) {
+	t.Chdir(filepath.Join("..", ".."))
+
+	// Mock m6i.large spot prices in eu-west-2a average $0.034675/h on
+	// Linux/UNIX and $0.126675/h on Windows
+	tests := []struct {
+		name         string
+		platform     string
+		hourlyCost   float64 // billed, 0 = none
+		wantOK       bool
+		wantDiscount float64
+	}{
+		{"Linux against the catalog price", "Linux/UNIX", 0, true, (1 - 0.034675/0.096) * 100},
+		// $0.096 plus 2 vCPUs of Windows licence at $0.046
+		{"Windows against the catalog price with licence", "Windows", 0, true, (1 - 0.126675/0.188) * 100},
+		{"Windows against billed cost", "Windows", 0.2, true, (1 - 0.126675/0.2) * 100},
+		{"no spot pricing for SQL Server licence-included", "Windows with SQL Server Standard", 0, false, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := model.EC2Instance{ID: "i-spot", InstanceType: "m6i.large", State: "running", AvailabilityZone: "eu-west-2a", Platform: tt.platform}
+			rec := model.Recommendation{InstanceID: inst.ID, InstanceType: inst.InstanceType, State: "running", Action: "Keep as-is",
+				HourlyCost: tt.hourlyCost, MonthlyCost: tt.hourlyCost * hoursPerMonth}
+
+			advice, ok := adviseSpot(context.Background(), inst, rec, &awsclient.MockCloudWatchClient{}, &awsclient.MockSpotPricing{}, 7)
+			if ok != tt.wantOK {
+				t.Fatalf("adviseSpot ok = %v, want %v", ok, tt.wantOK)
+			}
+			if !ok {
+				return
+			}
+			if math.Abs(advice.DiscountPercent-tt.wantDiscount) > 1e-6 {
+				t.Errorf("discount = %.2f%%, want %.2f%%", advice.DiscountPercent, tt.wantDiscount)
+			}
+			if advice.ExpectedSaving <= 0 {
+				t.Errorf("expected saving = %.2f, want it positive", advice.ExpectedSaving)
+			}
+		})
+	}
+}
+
+func TestUptimeShare(t *testing.T) {
+	start := time.Date(2026, 10, 5, 0, 0, 0, 0, time.UTC)
+	officeHours := func(t time.Time) float64 {
+		if t.Hour() >= 8 && t.Hour() < 18 {
+			return 30
+		}
+		return -1 // stopped, no datapoint
+	}
+
+	tests := []struct {
+		name   string
+		days   int
+		cpu    func(time.Time) float64
+		want   float64
+		wantOK bool
+	}{
+		{"always on", 14, func(time.Time) float64 { return 20 }, 1, true},
+		// Ten hours a day, measured from the first morning to the last evening
+		{"stopped overnight", 14, officeHours, 140.0 / (13*24 + 10), true},
+		{"less than a day", 1, officeHours, 0, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var points []model.CPUPoint
+			for _, p := range hourlyPoints(start, tt.days, tt.cpu) {
+				if p.Value >= 0 {
+					points = append(points, p)
+				}
+			}
+			got, ok := uptimeShare(points)
+			if ok != tt.wantOK || math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("uptimeShare = %.3f, %v; want %.3f, %v", got, ok, tt.want, tt.wantOK)
+			}
+		})
+	}
+}
@@ -0,0 +1,129 @@
+package analyser
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Approximate EBS list prices (us-east-1, USD per month)
+var ebsGiBMonthRates = map[string]float64{
+	"gp2":      0.10,
+	"gp3":      0.08,
+	"io1":      0.125,
+	"io2":      0.125,
+	"st1":      0.045,
+	"sc1":      0.015,
+	"standard": 0.05,
+}
+
+const (
+	hoursPerMonth           = 730
+	gp3FreeIops             = 3000
+	gp3FreeThroughput       = 125   // MB/s
+	gp3IopsRate             = 0.005 // per provisioned IOPS above the baseline
+	gp3ThroughputRate       = 0.04  // per MB/s above the baseline
+	provisionedIopsRate     = 0.065 // io1/io2 per provisioned IOPS
+	snapshotArchiveGiBMonth = 0.0125
+)
+
+// stoppedAtPattern matches the timestamp in e.g. "User initiated (2024-01-02 03:04:05 GMT)"
+var stoppedAtPattern = regexp.MustCompile(`\((\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}) GMT\)`)
+
+// analyseStopped builds the recommendation for a stopped instance. Compute is
+// not billed while stopped, so the ongoing cost it can save is the attached
+// EBS storage; the billed cost is reported as Cost Explorer returned it.
+func analyseStopped(inst model.EC2Instance, cost model.CostData, opts Options, now time.Time) model.Recommendation {
+	ebsCost := 0.0
+	var sizeGiB int32
+	for _, v := range inst.Volumes {
+		ebsCost += volumeMonthlyCost(v)
+		sizeGiB += v.SizeGiB
+	}
+
+	rec := model.Recommendation{
+		InstanceID:     inst.ID,
+		InstanceType:   inst.InstanceType,
+		State:          inst.State,
+		MonthlyCost:    cost.MonthlyCost,
+		HourlyCost:     cost.HourlyCost,
+		EBSMonthlyCost: ebsCost,
+		Action:         "Keep as-is",
+	}
+
+	storage := fmt.Sprintf("%d volume(s), %d GiB, $%.2f/mo EBS still billed", len(inst.Volumes), sizeGiB, ebsCost)
+
+	stoppedAt, ok := stoppedSince(inst)
+	if !ok {
+		rec.Action = "Review"
+		if !inst.LaunchTime.IsZero() {
+			rec.Reason = fmt.Sprintf("Stopped; stop time unknown, last launched %d days ago; %s.",
+				daysBetween(inst.LaunchTime, now), storage)
+		} else {
+			rec.Reason = fmt.Sprintf("Stopped; stop time unknown; %s.", storage)
+		}
+		return rec
+	}
+
+	days := daysBetween(stoppedAt, now)
+	if opts.StoppedDays <= 0 || days < opts.StoppedDays {
+		rec.Reason = fmt.Sprintf("Stopped %d days (threshold %d); %s.", days, opts.StoppedDays, storage)
+		return rec
+	}
+
+	// Snapshots only store used blocks, so the provisioned size is an upper bound
+	snapshotCost := float64(sizeGiB) * snapshotArchiveGiBMonth
+	rec.Action = "Snapshot & Terminate"
+	rec.EstimatedSaving = ebsCost - snapshotCost
+	if rec.EstimatedSaving < 0 {
+		rec.EstimatedSaving = 0
+	}
+	rec.Reason = fmt.Sprintf("Stopped %d days since %s; %s. Snapshot to the archive tier (at most $%.2f/mo) and terminate.",
+		days, stoppedAt.Format("2006-01-02"), storage, snapshotCost)
+	return rec
+}
+
+// stoppedSince parses the stop time EC2 records in StateTransitionReason
+func stoppedSince(inst model.EC2Instance) (time.Time, bool) {
+	m := stoppedAtPattern.FindStringSubmatch(inst.StateTransitionReason)
+	if m == nil {
+		return time.Time{}, false
+	}
+	t, err := time.Parse("2006-01-02 15:04:05", m[1])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+// volumeMonthlyCost estimates the monthly storage and provisioned-performance cost of a volume
+func volumeMonthlyCost(v model.EBSVolume) float64 {
+	rate, ok := ebsGiBMonthRates[v.VolumeType]
+	if !ok {
+		rate = ebsGiBMonthRates["gp2"]
+	}
+	cost := float64(v.SizeGiB) * rate
+
+	switch v.VolumeType {
+	case "gp3":
+		if v.Iops > gp3FreeIops {
+			cost += float64(v.Iops-gp3FreeIops) * gp3IopsRate
+		}
+		if v.Throughput > gp3FreeThroughput {
+			cost += float64(v.Throughput-gp3FreeThroughput) * gp3ThroughputRate
+		}
+	case "io1", "io2":
+		cost += float64(v.Iops) * provisionedIopsRate
+	}
+	return cost
+}
+
+// daysBetween returns whole days elapsed from t to now
+func daysBetween(t, now time.Time) int {
+	if now.Before(t) {
+		return 0
+	}
+	return int(now.Sub(t).Hours() / 24)
+}
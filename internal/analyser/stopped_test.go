@@ -0,0 +1,53 @@
+package analyser
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func TestAnalyseStopped(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	volumes := []model.EBSVolume{
+		{VolumeType: "gp3", SizeGiB: 100},             // $8.00
+		{VolumeType: "io1", SizeGiB: 100, Iops: 1000}, // $12.50 + $65.00
+	}
+	cost := model.CostData{MonthlyCost: 95.92, HourlyCost: 0.132}
+
+	tests := []struct {
+		name       string
+		reason     string
+		wantAction string
+		wantSaving float64
+		wantText   string
+	}{
+		{"long stopped", "User initiated (2026-06-02 08:15:31 GMT)", "Snapshot & Terminate", 85.5 - 200*snapshotArchiveGiBMonth, "Stopped 139 days since 2026-06-02"},
+		{"recently stopped", "User initiated (2026-10-01 08:15:31 GMT)", "Keep as-is", 0, "Stopped 18 days (threshold 30)"},
+		{"stop time unknown", "", "Review", 0, "stop time unknown"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := model.EC2Instance{ID: "i-1", InstanceType: "m5.large", State: "stopped", StateTransitionReason: tt.reason, Volumes: volumes}
+			rec := analyseStopped(inst, cost, Options{StoppedDays: 30}, now)
+
+			if rec.Action != tt.wantAction {
+				t.Errorf("action = %q, want %q", rec.Action, tt.wantAction)
+			}
+			if diff := rec.EstimatedSaving - tt.wantSaving; diff > 1e-9 || diff < -1e-9 {
+				t.Errorf("saving = %.4f, want %.4f", rec.EstimatedSaving, tt.wantSaving)
+			}
+			if !strings.Contains(rec.Reason, tt.wantText) {
+				t.Errorf("reason %q does not contain %q", rec.Reason, tt.wantText)
+			}
+			// The billed cost is kept; the EBS estimate is reported separately
+			if rec.MonthlyCost != cost.MonthlyCost || rec.HourlyCost != cost.HourlyCost {
+				t.Errorf("cost = %.2f/mo %.4f/h, want billed %.2f/mo %.4f/h", rec.MonthlyCost, rec.HourlyCost, cost.MonthlyCost, cost.HourlyCost)
+			}
+			if rec.EBSMonthlyCost != 85.5 {
+				t.Errorf("EBS cost = %.2f, want 85.50", rec.EBSMonthlyCost)
+			}
+		})
+	}
+}
@@ -0,0 +1,148 @@
+package apply
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/journal"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Resize changes an instance type using the stop/modify/start sequence.
+// Every step is written to the journal before it runs, so an interrupted
+// resize can always be traced and reverted.
+func Resize(
+	ctx context.Context,
+	r awsclient.InstanceResizer,
+	j *journal.Journal,
+	inst model.EC2Instance,
+	newType string,
+	operator string,
+) (journal.Change, error) {
+	return resize(ctx, r, j, journal.KindApply, inst, newType, operator, "")
+}
+
+// PlanRollback validates that a change can be reverted against the instance's current state.
+// Completed changes can be reverted, and so can failed ones whose type change
+// went through before a later step failed. It refuses when the instance has
+// drifted from the type the change left it on.
+func PlanRollback(j *journal.Journal, inst model.EC2Instance, changeID string) (journal.Change, error) {
+	c, ok := j.Get(changeID)
+	if !ok {
+		return journal.Change{}, fmt.Errorf("change %s not found in journal", changeID)
+	}
+
+	if (c.State != journal.StateCompleted && c.State != journal.StateFailed) || !c.Modified() {
+		return journal.Change{}, fmt.Errorf("change %s is %s; only completed changes, or failed ones that modified the type, can be rolled back", c.ID, c.State)
+	}
+
+	if inst.ID != c.InstanceID {
+		return journal.Change{}, fmt.Errorf("change %s applies to %s, not %s", c.ID, c.InstanceID, inst.ID)
+	}
+
+	if inst.InstanceType != c.NewType {
+		return journal.Change{}, fmt.Errorf(
+			"instance %s has drifted: journal expects %s but it is now %s; refusing to roll back",
+			inst.ID, c.NewType, inst.InstanceType,
+		)
+	}
+
+	return c, nil
+}
+
+// Rollback restores the original instance type recorded by a change that
+// PlanRollback accepts.
+func Rollback(
+	ctx context.Context,
+	r awsclient.InstanceResizer,
+	j *journal.Journal,
+	inst model.EC2Instance,
+	changeID string,
+	operator string,
+) (journal.Change, error) {
+	orig, err := PlanRollback(j, inst, changeID)
+	if err != nil {
+		return journal.Change{}, err
+	}
+
+	c, err := resize(ctx, r, j, journal.KindRollback, inst, orig.OriginalType, operator, orig.ID)
+	if err != nil {
+		return c, err
+	}
+
+	if err := j.Transition(orig.ID, journal.StateRolledBack, "reverted by "+c.ID); err != nil {
+		return c, err
+	}
+
+	return c, nil
+}
+
+// resize runs and journals the stop/modify/start sequence
+func resize(
+	ctx context.Context,
+	r awsclient.InstanceResizer,
+	j *journal.Journal,
+	kind string,
+	inst model.EC2Instance,
+	newType string,
+	operator string,
+	rollbackOf string,
+) (journal.Change, error) {
+	if inst.State != "running" && inst.State != "stopped" {
+		return journal.Change{}, fmt.Errorf("instance %s is %s; it must be running or stopped to resize", inst.ID, inst.State)
+	}
+
+	c, err := j.Begin(kind, inst.ID, inst.InstanceType, newType, operator, rollbackOf)
+	if err != nil {
+		return journal.Change{}, fmt.Errorf("failed to write journal: %w", err)
+	}
+
+	fail := func(stepErr error) (journal.Change, error) {
+		if err := j.Transition(c.ID, journal.StateFailed, stepErr.Error()); err != nil {
+			return c, fmt.Errorf("%v (journal update also failed: %v)", stepErr, err)
+		}
+		latest, _ := j.Get(c.ID)
+		return latest, stepErr
+	}
+
+	wasRunning := inst.State == "running"
+
+	if wasRunning {
+		if err := j.Transition(c.ID, journal.StateStopping, ""); err != nil {
+			return c, err
+		}
+		if err := r.StopInstance(ctx, inst.ID); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := j.Transition(c.ID, journal.StateModifying, fmt.Sprintf("%s -> %s", inst.InstanceType, newType)); err != nil {
+		return c, err
+	}
+	if err := r.ModifyInstanceType(ctx, inst.ID, newType); err != nil {
+		// Bring the service back on its original type before reporting the failure
+		if wasRunning {
+			if startErr := r.StartInstance(ctx, inst.ID); startErr != nil {
+				return fail(fmt.Errorf("%v; restart on original type also failed: %v", err, startErr))
+			}
+		}
+		return fail(err)
+	}
+
+	if wasRunning {
+		if err := j.Transition(c.ID, journal.StateStarting, ""); err != nil {
+			return c, err
+		}
+		if err := r.StartInstance(ctx, inst.ID); err != nil {
+			return fail(err)
+		}
+	}
+
+	if err := j.Transition(c.ID, journal.StateCompleted, ""); err != nil {
+		return c, err
+	}
+
+	latest, _ := j.Get(c.ID)
+	return latest, nil
+}
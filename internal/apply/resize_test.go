@@ -0,0 +1,198 @@
+package apply
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/awsclient"
+	"github.com/PanaAnt/cloud-optimiser/internal/journal"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+const testInstance = "i-1234567890abcdef0" // t3.micro, running
+
+// mockInstance reads an instance's current state through the mock client
+func mockInstance(t *testing.T, client *awsclient.MockClient, id string) model.EC2Instance {
+	t.Helper()
+	instances, err := client.ListInstances(context.Background())
+	if err != nil {
+		t.Fatalf("ListInstances: %v", err)
+	}
+	for _, inst := range instances {
+		if inst.ID == id {
+			return inst
+		}
+	}
+	t.Fatalf("instance %s not in mock data", id)
+	return model.EC2Instance{}
+}
+
+// setup points the mock client at the repo testdata and opens an empty journal
+func setup(t *testing.T) (*awsclient.MockClient, *journal.Journal) {
+	t.Helper()
+	t.Chdir(filepath.Join("..", ".."))
+	j, err := journal.Open(filepath.Join(t.TempDir(), "journal.json"))
+	if err != nil {
+		t.Fatalf("Open journal: %v", err)
+	}
+	return &awsclient.MockClient{}, j
+}
+
+func TestResizeRollbackRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	client, j := setup(t)
+
+	inst := mockInstance(t, client, testInstance)
+	applied, err := Resize(ctx, client, j, inst, "t3.nano", "tester")
+	if err != nil {
+		t.Fatalf("Resize: %v", err)
+	}
+	if applied.State != journal.StateCompleted || applied.OriginalType != "t3.micro" || applied.NewType != "t3.nano" {
+		t.Fatalf("unexpected applied change: %+v", applied)
+	}
+	wantStates := []string{journal.StatePending, journal.StateStopping, journal.StateModifying, journal.StateStarting, journal.StateCompleted}
+	if got := states(applied); strings.Join(got, ",") != strings.Join(wantStates, ",") {
+		t.Errorf("transitions = %v, want %v", got, wantStates)
+	}
+
+	inst = mockInstance(t, client, testInstance)
+	if inst.InstanceType != "t3.nano" {
+		t.Fatalf("instance type after resize = %s, want t3.nano", inst.InstanceType)
+	}
+
+	reverted, err := Rollback(ctx, client, j, inst, applied.ID, "tester")
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if reverted.Kind != journal.KindRollback || reverted.RollbackOf != applied.ID || reverted.NewType != "t3.micro" {
+		t.Errorf("unexpected rollback change: %+v", reverted)
+	}
+	if got := mockInstance(t, client, testInstance).InstanceType; got != "t3.micro" {
+		t.Errorf("instance type after rollback = %s, want t3.micro", got)
+	}
+
+	// The journal on disk records both changes and marks the original reverted
+	reopened, err := journal.Open(j.Path())
+	if err != nil {
+		t.Fatalf("reopen journal: %v", err)
+	}
+	if len(reopened.Changes) != 2 {
+		t.Fatalf("journal has %d changes, want 2", len(reopened.Changes))
+	}
+	if c, _ := reopened.Get(applied.ID); c.State != journal.StateRolledBack {
+		t.Errorf("original change state = %s, want %s", c.State, journal.StateRolledBack)
+	}
+
+	// A rolled back change cannot be rolled back again
+	if _, err := Rollback(ctx, client, j, mockInstance(t, client, testInstance), applied.ID, "tester"); err == nil {
+		t.Error("expected second rollback of the same change to fail")
+	}
+}
+
+func TestRollbackRefusesDrift(t *testing.T) {
+	ctx := context.Background()
+	client, j := setup(t)
+
+	applied, err := Resize(ctx, client, j, mockInstance(t, client, testInstance), "t3.nano", "tester")
+	if err != nil {
+		t.Fatalf("Resize: %v", err)
+	}
+
+	// Someone changes the type outside the tool
+	if err := client.ModifyInstanceType(ctx, testInstance, "t3.small"); err != nil {
+		t.Fatalf("ModifyInstanceType: %v", err)
+	}
+
+	_, err = Rollback(ctx, client, j, mockInstance(t, client, testInstance), applied.ID, "tester")
+	if err == nil || !strings.Contains(err.Error(), "drifted") {
+		t.Fatalf("Rollback error = %v, want drift refusal", err)
+	}
+	if got := mockInstance(t, client, testInstance).InstanceType; got != "t3.small" {
+		t.Errorf("instance type after refused rollback = %s, want t3.small", got)
+	}
+	if len(j.Changes) != 1 {
+		t.Errorf("refused rollback wrote %d journal entries, want none", len(j.Changes)-1)
+	}
+	if c, _ := j.Get(applied.ID); c.State != journal.StateCompleted {
+		t.Errorf("original change state = %s, want %s", c.State, journal.StateCompleted)
+	}
+}
+
+// states lists the states a change passed through
+func states(c journal.Change) []string {
+	var out []string
+	for _, tr := range c.Transitions {
+		out = append(out, tr.State)
+	}
+	return out
+}
+
+// startFails is a resizer whose first StartInstance call fails, as when there
+// is no capacity for the new type
+type startFails struct {
+	*awsclient.MockClient
+	failed bool
+}
+
+func (s *startFails) StartInstance(ctx context.Context, instanceID string) error {
+	if !s.failed {
+		s.failed = true
+		return errors.New("InsufficientInstanceCapacity")
+	}
+	return s.MockClient.StartInstance(ctx, instanceID)
+}
+
+func TestRollbackAfterFailedStart(t *testing.T) {
+	ctx := context.Background()
+	client, j := setup(t)
+	resizer := &startFails{MockClient: client}
+
+	failed, err := Resize(ctx, resizer, j, mockInstance(t, client, testInstance), "t3.nano", "tester")
+	if err == nil || failed.State != journal.StateFailed {
+		t.Fatalf("Resize = %+v, %v; want a failed change", failed, err)
+	}
+	if got := mockInstance(t, client, testInstance).InstanceType; got != "t3.nano" {
+		t.Fatalf("instance type after failed start = %s, want t3.nano", got)
+	}
+
+	reverted, err := Rollback(ctx, resizer, j, mockInstance(t, client, testInstance), failed.ID, "tester")
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if reverted.State != journal.StateCompleted || reverted.NewType != "t3.micro" {
+		t.Errorf("unexpected rollback change: %+v", reverted)
+	}
+	if got := mockInstance(t, client, testInstance).InstanceType; got != "t3.micro" {
+		t.Errorf("instance type after rollback = %s, want t3.micro", got)
+	}
+	if c, _ := j.Get(failed.ID); c.State != journal.StateRolledBack {
+		t.Errorf("failed change state = %s, want %s", c.State, journal.StateRolledBack)
+	}
+}
+
+// modifyFails is a resizer that rejects every type change
+type modifyFails struct {
+	*awsclient.MockClient
+}
+
+func (m modifyFails) ModifyInstanceType(ctx context.Context, instanceID, instanceType string) error {
+	return errors.New("Unsupported")
+}
+
+func TestRollbackRefusesFailedModify(t *testing.T) {
+	ctx := context.Background()
+	client, j := setup(t)
+
+	failed, err := Resize(ctx, modifyFails{client}, j, mockInstance(t, client, testInstance), "t3.nano", "tester")
+	if err == nil || failed.State != journal.StateFailed {
+		t.Fatalf("Resize = %+v, %v; want a failed change", failed, err)
+	}
+
+	_, err = Rollback(ctx, client, j, mockInstance(t, client, testInstance), failed.ID, "tester")
+	if err == nil || !strings.Contains(err.Error(), "is failed") {
+		t.Fatalf("Rollback error = %v, want a refusal", err)
+	}
+}
@@ -11,6 +11,10 @@ import (
 
 type CostExplorerClient interface {
 	GetInstanceCost(ctx context.Context, instanceID string, days int) (model.CostData, error)
+	GetCostByTags(ctx context.Context, tagKeys []string, days int) ([]model.TagCost, error)
+	GetCommitmentUtilisation(ctx context.Context, days int) ([]model.CommitmentUtilisation, error)
+	GetCommitmentCoverage(ctx context.Context, days int) ([]model.FamilyCoverage, error)
+	GetOnDemandCostByFamily(ctx context.Context, days int) ([]model.FamilyDailyCost, error)
 	IsMock() bool
 }
 
@@ -42,3 +42,97 @@ func (m *MockCostExplorer) GetInstanceCost(ctx context.Context, instanceID strin
 		HourlyCost:  0,
 	}, nil
 }
+
+// GetCostByTags reads mock tagged costs from testdata/cost_by_tag.json and
+// groups them by the requested tag keys, as Cost Explorer's TAG group-by would
+func (m *MockCostExplorer) GetCostByTags(ctx context.Context, tagKeys []string, days int) ([]model.TagCost, error) {
+	path := filepath.Join("testdata", "cost_by_tag.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock tag cost data: %w", err)
+	}
+
+	var entries []model.TagCost
+	if err := json.Unmarshal(file, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock tag cost data: %w", err)
+	}
+
+	var out []model.TagCost
+	index := map[string]int{}
+	for _, e := range entries {
+		tags := map[string]string{}
+		key := ""
+		for _, k := range tagKeys {
+			tags[k] = e.Tags[k]
+			key += k + "=" + e.Tags[k] + ";"
+		}
+
+		if i, ok := index[key]; ok {
+			out[i].MonthlyCost += e.MonthlyCost
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, model.TagCost{Tags: tags, MonthlyCost: e.MonthlyCost})
+	}
+
+	return out, nil
+}
+
+// GetCommitmentUtilisation reads mock RI and Savings Plans utilisation from testdata/commitment_utilisation.json
+func (m *MockCostExplorer) GetCommitmentUtilisation(ctx context.Context, days int) ([]model.CommitmentUtilisation, error) {
+	path := filepath.Join("testdata", "commitment_utilisation.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock commitment utilisation data: %w", err)
+	}
+
+	var util []model.CommitmentUtilisation
+	if err := json.Unmarshal(file, &util); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock commitment utilisation data: %w", err)
+	}
+
+	return util, nil
+}
+
+// GetCommitmentCoverage reads mock per-family coverage from testdata/commitment_coverage.json
+func (m *MockCostExplorer) GetCommitmentCoverage(ctx context.Context, days int) ([]model.FamilyCoverage, error) {
+	path := filepath.Join("testdata", "commitment_coverage.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock commitment coverage data: %w", err)
+	}
+
+	var coverage []model.FamilyCoverage
+	if err := json.Unmarshal(file, &coverage); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock commitment coverage data: %w", err)
+	}
+
+	return coverage, nil
+}
+
+// GetOnDemandCostByFamily reads mock daily on-demand cost per family from testdata/on_demand_daily.json
+func (m *MockCostExplorer) GetOnDemandCostByFamily(ctx context.Context, days int) ([]model.FamilyDailyCost, error) {
+	path := filepath.Join("testdata", "on_demand_daily.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock on-demand cost data: %w", err)
+	}
+
+	var costs []model.FamilyDailyCost
+	if err := json.Unmarshal(file, &costs); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock on-demand cost data: %w", err)
+	}
+
+	// Keep the most recent days, as the window would
+	for i := range costs {
+		if n := len(costs[i].Daily); days > 0 && n > days {
+			costs[i].Daily = costs[i].Daily[n-days:]
+		}
+	}
+
+	return costs, nil
+}
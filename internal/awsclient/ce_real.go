@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -96,3 +97,350 @@ func (r *RealCostExplorer) GetInstanceCost(ctx context.Context, instanceID strin
 	}, nil
 }
 
+// ec2ComputeService is the Cost Explorer SERVICE value for instance usage
+const ec2ComputeService = "Amazon Elastic Compute Cloud - Compute"
+
+// GetCostByTags retrieves EC2 compute cost grouped by up to two cost allocation tags
+func (r *RealCostExplorer) GetCostByTags(ctx context.Context, tagKeys []string, days int) ([]model.TagCost, error) {
+	if len(tagKeys) == 0 || len(tagKeys) > 2 {
+		return nil, fmt.Errorf("cost explorer can group by 1 or 2 tags, got %d", len(tagKeys))
+	}
+
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(days) * 24 * time.Hour)
+
+	var groupBy []ceTypes.GroupDefinition
+	for _, k := range tagKeys {
+		groupBy = append(groupBy, ceTypes.GroupDefinition{
+			Type: ceTypes.GroupDefinitionTypeTag,
+			Key:  aws.String(k),
+		})
+	}
+
+	input := &costexplorer.GetCostAndUsageInput{
+		Metrics:     []string{"UnblendedCost"},
+		Granularity: ceTypes.GranularityMonthly,
+		TimePeriod: &ceTypes.DateInterval{
+			Start: aws.String(start.Format("2006-01-02")),
+			End:   aws.String(end.Format("2006-01-02")),
+		},
+		Filter: &ceTypes.Expression{
+			Dimensions: &ceTypes.DimensionValues{
+				Key:    ceTypes.DimensionService,
+				Values: []string{ec2ComputeService},
+			},
+		},
+		GroupBy: groupBy,
+	}
+
+	totals := map[string]float64{}
+	tagsByKey := map[string]map[string]string{}
+	var order []string
+
+	for {
+		resp, err := r.ce.GetCostAndUsage(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("GetCostAndUsage failed: %w", err)
+		}
+
+		for _, result := range resp.ResultsByTime {
+			for _, group := range result.Groups {
+				// Tag group keys look like "Team$Data"; "Team$" means untagged
+				tags := map[string]string{}
+				for i, k := range group.Keys {
+					if i < len(tagKeys) {
+						_, value, _ := strings.Cut(k, "$")
+						tags[tagKeys[i]] = value
+					}
+				}
+				key := strings.Join(group.Keys, ";")
+
+				metric, ok := group.Metrics["UnblendedCost"]
+				if !ok || metric.Amount == nil {
+					continue
+				}
+				val, err := strconv.ParseFloat(aws.ToString(metric.Amount), 64)
+				if err != nil {
+					continue // Skip invalid amounts
+				}
+
+				if _, seen := tagsByKey[key]; !seen {
+					tagsByKey[key] = tags
+					order = append(order, key)
+				}
+				totals[key] += val
+			}
+		}
+
+		if resp.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = resp.NextPageToken
+	}
+
+	// Normalise the window total to an approximate 30-day month
+	var out []model.TagCost
+	for _, key := range order {
+		out = append(out, model.TagCost{
+			Tags:        tagsByKey[key],
+			MonthlyCost: totals[key] / float64(days) * 30,
+		})
+	}
+
+	return out, nil
+}
+
+// GetCommitmentUtilisation retrieves utilisation per Reserved Instance and per Savings Plan
+func (r *RealCostExplorer) GetCommitmentUtilisation(ctx context.Context, days int) ([]model.CommitmentUtilisation, error) {
+	period := lastDays(days)
+	var out []model.CommitmentUtilisation
+
+	// Reserved Instances, one group per subscription
+	riInput := &costexplorer.GetReservationUtilizationInput{
+		TimePeriod: period,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Type: ceTypes.GroupDefinitionTypeDimension, Key: aws.String(string(ceTypes.DimensionSubscriptionId))},
+		},
+	}
+	for {
+		resp, err := r.ce.GetReservationUtilization(ctx, riInput)
+		if err != nil {
+			return nil, fmt.Errorf("GetReservationUtilization failed: %w", err)
+		}
+
+		for _, byTime := range resp.UtilizationsByTime {
+			for _, g := range byTime.Groups {
+				if g.Utilization == nil {
+					continue
+				}
+				// leaseId is the ReservedInstancesId EC2 reports
+				id := attribute(g.Attributes, "leaseId")
+				if id == "" {
+					id = aws.ToString(g.Value)
+				}
+				out = append(out, model.CommitmentUtilisation{
+					ID:             id,
+					UtilisationPct: parseAmount(g.Utilization.UtilizationPercentage),
+				})
+			}
+		}
+
+		if resp.NextPageToken == nil {
+			break
+		}
+		riInput.NextPageToken = resp.NextPageToken
+	}
+
+	// Savings Plans, one detail per plan ARN
+	spPaginator := costexplorer.NewGetSavingsPlansUtilizationDetailsPaginator(r.ce, &costexplorer.GetSavingsPlansUtilizationDetailsInput{
+		TimePeriod: period,
+	})
+	for spPaginator.HasMorePages() {
+		page, err := spPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("GetSavingsPlansUtilizationDetails failed: %w", err)
+		}
+
+		for _, d := range page.SavingsPlansUtilizationDetails {
+			if d.Utilization == nil {
+				continue
+			}
+			out = append(out, model.CommitmentUtilisation{
+				ID:             aws.ToString(d.SavingsPlanArn),
+				UtilisationPct: parseAmount(d.Utilization.UtilizationPercentage),
+			})
+		}
+	}
+
+	return out, nil
+}
+
+// GetCommitmentCoverage retrieves RI hour coverage and Savings Plans spend coverage per instance family
+func (r *RealCostExplorer) GetCommitmentCoverage(ctx context.Context, days int) ([]model.FamilyCoverage, error) {
+	period := lastDays(days)
+	reserved := map[string]float64{}
+	running := map[string]float64{}
+	coverage := map[string]*model.FamilyCoverage{}
+	var order []string
+
+	familyEntry := func(family string) *model.FamilyCoverage {
+		if c, ok := coverage[family]; ok {
+			return c
+		}
+		c := &model.FamilyCoverage{Family: family}
+		coverage[family] = c
+		order = append(order, family)
+		return c
+	}
+
+	// RI coverage is reported per instance type; roll it up by family
+	riInput := &costexplorer.GetReservationCoverageInput{
+		TimePeriod: period,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Type: ceTypes.GroupDefinitionTypeDimension, Key: aws.String(string(ceTypes.DimensionInstanceType))},
+		},
+	}
+	for {
+		resp, err := r.ce.GetReservationCoverage(ctx, riInput)
+		if err != nil {
+			return nil, fmt.Errorf("GetReservationCoverage failed: %w", err)
+		}
+
+		for _, byTime := range resp.CoveragesByTime {
+			for _, g := range byTime.Groups {
+				if g.Coverage == nil || g.Coverage.CoverageHours == nil {
+					continue
+				}
+				family, _, _ := strings.Cut(attribute(g.Attributes, "instanceType"), ".")
+				if family == "" {
+					continue
+				}
+				familyEntry(family)
+				reserved[family] += parseAmount(g.Coverage.CoverageHours.ReservedHours)
+				running[family] += parseAmount(g.Coverage.CoverageHours.TotalRunningHours)
+			}
+		}
+
+		if resp.NextPageToken == nil {
+			break
+		}
+		riInput.NextPageToken = resp.NextPageToken
+	}
+	for family, total := range running {
+		if total > 0 {
+			coverage[family].RICoveragePct = reserved[family] / total * 100
+		}
+	}
+
+	spPaginator := costexplorer.NewGetSavingsPlansCoveragePaginator(r.ce, &costexplorer.GetSavingsPlansCoverageInput{
+		TimePeriod: period,
+		GroupBy: []ceTypes.GroupDefinition{
+			{Type: ceTypes.GroupDefinitionTypeDimension, Key: aws.String(string(ceTypes.DimensionInstanceTypeFamily))},
+		},
+		Filter: &ceTypes.Expression{
+			Dimensions: &ceTypes.DimensionValues{
+				Key:    ceTypes.DimensionService,
+				Values: []string{ec2ComputeService},
+			},
+		},
+	})
+	for spPaginator.HasMorePages() {
+		page, err := spPaginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("GetSavingsPlansCoverage failed: %w", err)
+		}
+
+		for _, c := range page.SavingsPlansCoverages {
+			if c.Coverage == nil {
+				continue
+			}
+			family := attribute(c.Attributes, "INSTANCE_TYPE_FAMILY", "instanceTypeFamily")
+			if family == "" {
+				continue
+			}
+			familyEntry(family).SPCoveragePct = parseAmount(c.Coverage.CoveragePercentage)
+		}
+	}
+
+	var out []model.FamilyCoverage
+	for _, family := range order {
+		out = append(out, *coverage[family])
+	}
+	return out, nil
+}
+
+// lastDays returns the Cost Explorer period covering the last days
+func lastDays(days int) *ceTypes.DateInterval {
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(days) * 24 * time.Hour)
+	return &ceTypes.DateInterval{
+		Start: aws.String(start.Format("2006-01-02")),
+		End:   aws.String(end.Format("2006-01-02")),
+	}
+}
+
+// attribute returns the first matching group attribute, ignoring key case
+func attribute(attrs map[string]string, keys ...string) string {
+	for _, want := range keys {
+		for k, v := range attrs {
+			if strings.EqualFold(k, want) {
+				return v
+			}
+		}
+	}
+	return ""
+}
+
+// parseAmount parses a Cost Explorer numeric string, treating bad values as 0
+func parseAmount(s *string) float64 {
+	v, err := strconv.ParseFloat(aws.ToString(s), 64)
+	if err != nil {
+		return 0
+	}
+	return v
+}
+
+// onDemandPurchaseType is the Cost Explorer PURCHASE_TYPE value for on-demand usage
+const onDemandPurchaseType = "On Demand Instances"
+
+// GetOnDemandCostByFamily retrieves daily on-demand EC2 compute cost grouped by instance family
+func (r *RealCostExplorer) GetOnDemandCostByFamily(ctx context.Context, days int) ([]model.FamilyDailyCost, error) {
+	input := &costexplorer.GetCostAndUsageInput{
+		Metrics:     []string{"UnblendedCost"},
+		Granularity: ceTypes.GranularityDaily,
+		TimePeriod:  lastDays(days),
+		Filter: &ceTypes.Expression{
+			And: []ceTypes.Expression{
+				{Dimensions: &ceTypes.DimensionValues{Key: ceTypes.DimensionService, Values: []string{ec2ComputeService}}},
+				{Dimensions: &ceTypes.DimensionValues{Key: ceTypes.DimensionPurchaseType, Values: []string{onDemandPurchaseType}}},
+			},
+		},
+		GroupBy: []ceTypes.GroupDefinition{
+			{Type: ceTypes.GroupDefinitionTypeDimension, Key: aws.String(string(ceTypes.DimensionInstanceTypeFamily))},
+		},
+	}
+
+	daily := map[string][]float64{}
+	var order []string
+	day := 0
+
+	for {
+		resp, err := r.ce.GetCostAndUsage(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("GetCostAndUsage failed: %w", err)
+		}
+
+		for _, result := range resp.ResultsByTime {
+			for _, group := range result.Groups {
+				if len(group.Keys) == 0 {
+					continue
+				}
+				family := group.Keys[0]
+				if _, seen := daily[family]; !seen {
+					order = append(order, family)
+				}
+				// Families appearing late get zero for the days before
+				for len(daily[family]) < day {
+					daily[family] = append(daily[family], 0)
+				}
+				daily[family] = append(daily[family], parseAmount(group.Metrics["UnblendedCost"].Amount))
+			}
+			day++
+		}
+
+		if resp.NextPageToken == nil {
+			break
+		}
+		input.NextPageToken = resp.NextPageToken
+	}
+
+	var out []model.FamilyDailyCost
+	for _, family := range order {
+		d := daily[family]
+		for len(d) < day {
+			d = append(d, 0)
+		}
+		out = append(out, model.FamilyDailyCost{Family: family, Daily: d})
+	}
+	return out, nil
+}
@@ -0,0 +1,47 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/config"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+type CommitmentsClient interface {
+	ListReservedInstances(ctx context.Context) ([]model.ReservedInstance, error)
+	ListSavingsPlans(ctx context.Context) ([]model.SavingsPlan, error)
+	IsMock() bool
+}
+
+// NewCommitments creates a Reserved Instance and Savings Plans client based on the provided configuration.
+// Returns an error if real AWS client creation fails and mock mode is not enabled.
+func NewCommitments(ctx context.Context, cfg Config) (CommitmentsClient, error) {
+	// Forced mock via flag
+	if cfg.UseMock {
+		logging.Debug("Commitments: Using MOCK (flag override)")
+		return &MockCommitments{}, nil
+	}
+
+	appCfg, err := config.LoadConfig()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Could not load config: %v, defaulting to mock", err))
+		return &MockCommitments{}, nil
+	}
+
+	if appCfg.Mode == "mock" {
+		logging.Debug("Commitments: Using MOCK (from config)")
+		return &MockCommitments{}, nil
+	}
+
+	// Attempt to create real AWS client
+	logging.Debug("Commitments: Attempting to create real AWS client")
+	client, err := NewRealCommitments(ctx, cfg.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create real commitments client: %w", err)
+	}
+
+	logging.Debug("Commitments: Using REAL AWS")
+	return client, nil
+}
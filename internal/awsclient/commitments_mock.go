@@ -0,0 +1,60 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+type MockCommitments struct{}
+
+// IsMock returns true indicating mock client
+func (m *MockCommitments) IsMock() bool {
+	return true
+}
+
+// ListReservedInstances reads mock Reserved Instances from testdata/reserved_instances.json
+func (m *MockCommitments) ListReservedInstances(ctx context.Context) ([]model.ReservedInstance, error) {
+	path := filepath.Join("testdata", "reserved_instances.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock reserved instance data: %w", err)
+	}
+
+	var ris []model.ReservedInstance
+	if err := json.Unmarshal(file, &ris); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock reserved instance data: %w", err)
+	}
+
+	for i := range ris {
+		ris[i].Start, ris[i].End = mockTime(ris[i].Start), mockTime(ris[i].End)
+	}
+
+	return ris, nil
+}
+
+// ListSavingsPlans reads mock Savings Plans from testdata/savings_plans.json
+func (m *MockCommitments) ListSavingsPlans(ctx context.Context) ([]model.SavingsPlan, error) {
+	path := filepath.Join("testdata", "savings_plans.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock savings plan data: %w", err)
+	}
+
+	var plans []model.SavingsPlan
+	if err := json.Unmarshal(file, &plans); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock savings plan data: %w", err)
+	}
+
+	for i := range plans {
+		plans[i].Start, plans[i].End = mockTime(plans[i].Start), mockTime(plans[i].End)
+	}
+
+	return plans, nil
+}
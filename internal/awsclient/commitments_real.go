@@ -0,0 +1,128 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+	"github.com/aws/aws-sdk-go-v2/service/savingsplans"
+	sptypes "github.com/aws/aws-sdk-go-v2/service/savingsplans/types"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+type RealCommitments struct {
+	ec2Client *ec2.Client
+	sp        *savingsplans.Client
+}
+
+// NewRealCommitments creates a real AWS Reserved Instance and Savings Plans client with optional profile
+func NewRealCommitments(ctx context.Context, profile string) (*RealCommitments, error) {
+	opts := []func(*config.LoadOptions) error{}
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &RealCommitments{
+		ec2Client: ec2.NewFromConfig(cfg),
+		sp:        savingsplans.NewFromConfig(cfg),
+	}, nil
+}
+
+// IsMock returns false indicating real AWS client
+func (r *RealCommitments) IsMock() bool {
+	return false
+}
+
+// ListReservedInstances retrieves active EC2 Reserved Instances
+func (r *RealCommitments) ListReservedInstances(ctx context.Context) ([]model.ReservedInstance, error) {
+	out, err := r.ec2Client.DescribeReservedInstances(ctx, &ec2.DescribeReservedInstancesInput{
+		Filters: []ec2types.Filter{{Name: aws.String("state"), Values: []string{"active"}}},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("DescribeReservedInstances failed: %w", err)
+	}
+
+	var ris []model.ReservedInstance
+	for _, ri := range out.ReservedInstances {
+		hourly := float64(aws.ToFloat32(ri.UsagePrice))
+		for _, c := range ri.RecurringCharges {
+			if c.Frequency == ec2types.RecurringChargeFrequencyHourly {
+				hourly += aws.ToFloat64(c.Amount)
+			}
+		}
+
+		res := model.ReservedInstance{
+			ID:            aws.ToString(ri.ReservedInstancesId),
+			InstanceType:  string(ri.InstanceType),
+			Count:         int(aws.ToInt32(ri.InstanceCount)),
+			Platform:      string(ri.ProductDescription),
+			OfferingClass: string(ri.OfferingClass),
+			OfferingType:  string(ri.OfferingType),
+			FixedPrice:    float64(aws.ToFloat32(ri.FixedPrice)),
+			HourlyPrice:   hourly,
+			Start:         aws.ToTime(ri.Start),
+			End:           aws.ToTime(ri.End),
+		}
+		if ri.Scope == ec2types.ScopeAvailabilityZone {
+			res.AvailabilityZone = aws.ToString(ri.AvailabilityZone)
+		}
+		ris = append(ris, res)
+	}
+
+	return ris, nil
+}
+
+// ListSavingsPlans retrieves active Compute and EC2 Instance Savings Plans
+func (r *RealCommitments) ListSavingsPlans(ctx context.Context) ([]model.SavingsPlan, error) {
+	var plans []model.SavingsPlan
+	var next *string
+
+	for {
+		out, err := r.sp.DescribeSavingsPlans(ctx, &savingsplans.DescribeSavingsPlansInput{
+			States:    []sptypes.SavingsPlanState{sptypes.SavingsPlanStateActive},
+			NextToken: next,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("DescribeSavingsPlans failed: %w", err)
+		}
+
+		for _, p := range out.SavingsPlans {
+			if p.SavingsPlanType != sptypes.SavingsPlanTypeCompute && p.SavingsPlanType != sptypes.SavingsPlanTypeEc2Instance {
+				continue // SageMaker plans do not apply to EC2
+			}
+			commitment, _ := strconv.ParseFloat(aws.ToString(p.Commitment), 64)
+			start, _ := time.Parse(time.RFC3339, aws.ToString(p.Start))
+			end, _ := time.Parse(time.RFC3339, aws.ToString(p.End))
+
+			plans = append(plans, model.SavingsPlan{
+				ID:             aws.ToString(p.SavingsPlanArn),
+				Type:           string(p.SavingsPlanType),
+				InstanceFamily: aws.ToString(p.Ec2InstanceFamily),
+				Region:         aws.ToString(p.Region),
+				Commitment:     commitment,
+				TermYears:      int(p.TermDurationInSeconds / (365 * 24 * 3600)),
+				PaymentOption:  string(p.PaymentOption),
+				Start:          start,
+				End:            end,
+			})
+		}
+
+		if out.NextToken == nil || aws.ToString(out.NextToken) == "" {
+			break
+		}
+		next = out.NextToken
+	}
+
+	return plans, nil
+}
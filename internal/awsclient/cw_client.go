@@ -11,6 +11,10 @@ import (
 
 type CloudWatchClient interface {
 	GetCpuUtilisation(ctx context.Context, instanceID string, hours int) (model.CPUSampleSeries, error)
+	GetNetworkActivity(ctx context.Context, instanceID string, hours int) (model.NetworkActivity, error)
+	GetMemoryUtilisation(ctx context.Context, instanceID string, hours int) (model.MemorySampleSeries, error)
+	GetCreditMetrics(ctx context.Context, instanceID string, hours int) (model.CreditMetrics, error)
+	GetHourlyCpu(ctx context.Context, instanceID string, days int) (model.CPUHistory, error)
 	IsMock() bool
 }
 
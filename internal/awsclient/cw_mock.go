@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"github.com/PanaAnt/cloud-optimiser/internal/model"
 )
@@ -17,7 +18,9 @@ func (m *MockCloudWatchClient) IsMock() bool {
 	return true
 }
 
-// GetCpuUtilisation reads mock CPU metrics from testdata/metrics.json
+// GetCpuUtilisation reads mock CPU metrics from testdata/metrics.json. Each
+// entry is one 5 minute period ending now; null marks a period with no
+// datapoint.
 func (m *MockCloudWatchClient) GetCpuUtilisation(ctx context.Context, instanceID string, hours int) (model.CPUSampleSeries, error) {
 	path := filepath.Join("testdata", "metrics.json")
 
@@ -26,19 +29,126 @@ func (m *MockCloudWatchClient) GetCpuUtilisation(ctx context.Context, instanceID
 		return model.CPUSampleSeries{}, fmt.Errorf("failed to read mock metrics: %w", err)
 	}
 
-	var data map[string][]float64
+	var data map[string][]*float64
 	if err := json.Unmarshal(file, &data); err != nil {
 		return model.CPUSampleSeries{}, fmt.Errorf("failed to unmarshal mock metrics: %w", err)
 	}
 
-	samples, ok := data[instanceID]
+	const period = 300 // mock samples stand in for 5 minute averages
+
+	// Return empty samples if instance not found (not an error)
+	series := model.CPUSampleSeries{
+		InstanceID:    instanceID,
+		Samples:       []float64{},
+		PeriodSeconds: period,
+		WindowSeconds: len(data[instanceID]) * period, // the mock window is the periods listed
+	}
+	start := time.Now().UTC().Truncate(period * time.Second).Add(-time.Duration(series.WindowSeconds) * time.Second)
+	for i, v := range data[instanceID] {
+		if v == nil {
+			continue
+		}
+		series.Samples = append(series.Samples, *v)
+		series.Timestamps = append(series.Timestamps, start.Add(time.Duration(i*period)*time.Second))
+	}
+	return series, nil
+}
+
+// GetNetworkActivity reads mock network totals from testdata/network.json
+func (m *MockCloudWatchClient) GetNetworkActivity(ctx context.Context, instanceID string, hours int) (model.NetworkActivity, error) {
+	path := filepath.Join("testdata", "network.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return model.NetworkActivity{}, fmt.Errorf("failed to read mock network metrics: %w", err)
+	}
+
+	var data map[string]model.NetworkActivity
+	if err := json.Unmarshal(file, &data); err != nil {
+		return model.NetworkActivity{}, fmt.Errorf("failed to unmarshal mock network metrics: %w", err)
+	}
+
+	activity, ok := data[instanceID]
 	if !ok {
-		// Return empty samples if instance not found (not an error)
-		samples = []float64{}
+		// Return empty activity if instance not found (not an error)
+		return model.NetworkActivity{InstanceID: instanceID}, nil
+	}
+
+	activity.InstanceID = instanceID
+	return activity, nil
+}
+
+// GetMemoryUtilisation reads mock memory metrics from testdata/memory.json
+func (m *MockCloudWatchClient) GetMemoryUtilisation(ctx context.Context, instanceID string, hours int) (model.MemorySampleSeries, error) {
+	path := filepath.Join("testdata", "memory.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return model.MemorySampleSeries{}, fmt.Errorf("failed to read mock memory metrics: %w", err)
+	}
+
+	var data map[string][]float64
+	if err := json.Unmarshal(file, &data); err != nil {
+		return model.MemorySampleSeries{}, fmt.Errorf("failed to unmarshal mock memory metrics: %w", err)
 	}
 
-	return model.CPUSampleSeries{
+	// Instances without the CloudWatch agent have no samples (not an error)
+	return model.MemorySampleSeries{
 		InstanceID: instanceID,
-		Samples:    samples,
+		Samples:    data[instanceID],
 	}, nil
-}
\ No newline at end of file
+}
+
+// GetCreditMetrics reads mock CPU credit metrics from testdata/credits.json
+func (m *MockCloudWatchClient) GetCreditMetrics(ctx context.Context, instanceID string, hours int) (model.CreditMetrics, error) {
+	path := filepath.Join("testdata", "credits.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return model.CreditMetrics{}, fmt.Errorf("failed to read mock credit metrics: %w", err)
+	}
+
+	var data map[string]model.CreditMetrics
+	if err := json.Unmarshal(file, &data); err != nil {
+		return model.CreditMetrics{}, fmt.Errorf("failed to unmarshal mock credit metrics: %w", err)
+	}
+
+	// Return empty metrics if instance not found (not an error)
+	credits := data[instanceID]
+	credits.InstanceID = instanceID
+	return credits, nil
+}
+
+// GetHourlyCpu reads mock hourly CPU history from testdata/hourly_cpu.json,
+// keeping the last days of it
+func (m *MockCloudWatchClient) GetHourlyCpu(ctx context.Context, instanceID string, days int) (model.CPUHistory, error) {
+	path := filepath.Join("testdata", "hourly_cpu.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return model.CPUHistory{}, fmt.Errorf("failed to read mock hourly CPU: %w", err)
+	}
+
+	var data map[string]struct {
+		Start  time.Time `json:"start"`
+		Values []float64 `json:"values"`
+	}
+	if err := json.Unmarshal(file, &data); err != nil {
+		return model.CPUHistory{}, fmt.Errorf("failed to unmarshal mock hourly CPU: %w", err)
+	}
+
+	// Return empty history if instance not found (not an error)
+	history := model.CPUHistory{InstanceID: instanceID}
+	series := data[instanceID]
+	skip := 0
+	if days > 0 && len(series.Values) > days*24 {
+		skip = len(series.Values) - days*24
+	}
+	for i := skip; i < len(series.Values); i++ {
+		history.Points = append(history.Points, model.CPUPoint{
+			Time:  series.Start.Add(time.Duration(i) * time.Hour),
+			Value: series.Values[i],
+		})
+	}
+	return history, nil
+}
@@ -3,6 +3,7 @@ package awsclient
 import (
 	"context"
 	"fmt"
+	"math"
 	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +14,9 @@ import (
 	"github.com/PanaAnt/cloud-optimiser/internal/model"
 )
 
+// cpuPeriodSeconds is the CPU sample granularity (5 minute intervals)
+const cpuPeriodSeconds = 300
+
 type RealCloudWatchClient struct {
 	cw *cloudwatch.Client
 }
@@ -52,6 +56,7 @@ func (r *RealCloudWatchClient) GetCpuUtilisation(
 	input := &cloudwatch.GetMetricDataInput{
 		StartTime: aws.Time(start),
 		EndTime:   aws.Time(end),
+		ScanBy:    cloudwatchtypes.ScanByTimestampAscending,
 		MetricDataQueries: []cloudwatchtypes.MetricDataQuery{
 			{
 				Id: aws.String(metricID),
@@ -66,7 +71,7 @@ func (r *RealCloudWatchClient) GetCpuUtilisation(
 							},
 						},
 					},
-					Period: aws.Int32(300), // 5 minute intervals
+					Period: aws.Int32(cpuPeriodSeconds),
 					Stat:   aws.String("Average"),
 				},
 			},
@@ -78,18 +83,284 @@ func (r *RealCloudWatchClient) GetCpuUtilisation(
 		return model.CPUSampleSeries{}, fmt.Errorf("GetMetricData failed: %w", err)
 	}
 
-	var samples []float64
+	series := model.CPUSampleSeries{
+		InstanceID:    instanceID,
+		PeriodSeconds: cpuPeriodSeconds,
+		WindowSeconds: hours * 3600,
+	}
 	for _, res := range result.MetricDataResults {
 		if aws.ToString(res.Id) == metricID {
-			samples = append(samples, res.Values...)
+			series.Samples = append(series.Samples, res.Values...)
+			series.Timestamps = append(series.Timestamps, res.Timestamps...)
 		}
 	}
 
-	return model.CPUSampleSeries{
-		InstanceID: instanceID,
-		Samples:    samples,
-	}, nil
+	return series, nil
+}
+
+// GetNetworkActivity retrieves network totals and, when the CloudWatch agent
+// publishes it, the established TCP connection count for an instance
+func (r *RealCloudWatchClient) GetNetworkActivity(
+	ctx context.Context,
+	instanceID string,
+	hours int,
+) (model.NetworkActivity, error) {
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(hours) * time.Hour)
+
+	dimensions := []cloudwatchtypes.Dimension{
+		{
+			Name:  aws.String("InstanceId"),
+			Value: aws.String(instanceID),
+		},
+	}
+
+	query := func(id, namespace, metric, stat string) cloudwatchtypes.MetricDataQuery {
+		return cloudwatchtypes.MetricDataQuery{
+			Id: aws.String(id),
+			MetricStat: &cloudwatchtypes.MetricStat{
+				Metric: &cloudwatchtypes.Metric{
+					Namespace:  aws.String(namespace),
+					MetricName: aws.String(metric),
+					Dimensions: dimensions,
+				},
+				Period: aws.Int32(3600), // hourly is plenty for long idle windows
+				Stat:   aws.String(stat),
+			},
+		}
+	}
+
+	// Five-minute sums (the basic monitoring resolution) give the peak rate
+	peakIn, peakOut := query("peakIn", "AWS/EC2", "NetworkIn", "Sum"), query("peakOut", "AWS/EC2", "NetworkOut", "Sum")
+	peakIn.MetricStat.Period, peakOut.MetricStat.Period = aws.Int32(300), aws.Int32(300)
+	peakIn.ReturnData, peakOut.ReturnData = aws.Bool(false), aws.Bool(false)
+
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		MetricDataQueries: []cloudwatchtypes.MetricDataQuery{
+			query("netIn", "AWS/EC2", "NetworkIn", "Sum"),
+			query("netOut", "AWS/EC2", "NetworkOut", "Sum"),
+			query("conns", "CWAgent", "netstat_tcp_established", "Maximum"),
+			peakIn,
+			peakOut,
+			{
+				Id:         aws.String("peakRate"),
+				Expression: aws.String("(FILL(peakIn, 0) + FILL(peakOut, 0)) / PERIOD(peakIn)"),
+			},
+		},
+	}
+
+	activity := model.NetworkActivity{InstanceID: instanceID}
+
+	paginator := cloudwatch.NewGetMetricDataPaginator(r.cw, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return model.NetworkActivity{}, fmt.Errorf("GetMetricData failed: %w", err)
+		}
+
+		for _, res := range page.MetricDataResults {
+			switch aws.ToString(res.Id) {
+			case "netIn":
+				activity.NetworkInBytes += sum(res.Values)
+			case "netOut":
+				activity.NetworkOutBytes += sum(res.Values)
+			case "conns":
+				activity.Connections = append(activity.Connections, res.Values...)
+			case "peakRate":
+				for _, v := range res.Values {
+					activity.PeakBytesPerSec = math.Max(activity.PeakBytesPerSec, v)
+				}
+			}
+		}
+	}
+
+	return activity, nil
 }
 
+// GetMemoryUtilisation retrieves mem_used_percent published by the CloudWatch
+// agent. The agent must be configured to append the InstanceId dimension;
+// without it no samples are returned.
+func (r *RealCloudWatchClient) GetMemoryUtilisation(
+	ctx context.Context,
+	instanceID string,
+	hours int,
+) (model.MemorySampleSeries, error) {
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(hours) * time.Hour)
+	metricID := "memMetric"
 
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		MetricDataQueries: []cloudwatchtypes.MetricDataQuery{
+			{
+				Id: aws.String(metricID),
+				MetricStat: &cloudwatchtypes.MetricStat{
+					Metric: &cloudwatchtypes.Metric{
+						Namespace:  aws.String("CWAgent"),
+						MetricName: aws.String("mem_used_percent"),
+						Dimensions: []cloudwatchtypes.Dimension{
+							{
+								Name:  aws.String("InstanceId"),
+								Value: aws.String(instanceID),
+							},
+						},
+					},
+					Period: aws.Int32(300), // 5 minute intervals
+					Stat:   aws.String("Maximum"),
+				},
+			},
+		},
+	}
+
+	series := model.MemorySampleSeries{InstanceID: instanceID}
+
+	paginator := cloudwatch.NewGetMetricDataPaginator(r.cw, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return model.MemorySampleSeries{}, fmt.Errorf("GetMetricData failed: %w", err)
+		}
+		for _, res := range page.MetricDataResults {
+			if aws.ToString(res.Id) == metricID {
+				series.Samples = append(series.Samples, res.Values...)
+			}
+		}
+	}
 
+	return series, nil
+}
+
+// GetCreditMetrics retrieves hourly CPU credit balance, usage and unlimited-mode
+// surplus charges for a burstable instance
+func (r *RealCloudWatchClient) GetCreditMetrics(
+	ctx context.Context,
+	instanceID string,
+	hours int,
+) (model.CreditMetrics, error) {
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(hours) * time.Hour)
+
+	query := func(id, metric, stat string) cloudwatchtypes.MetricDataQuery {
+		return cloudwatchtypes.MetricDataQuery{
+			Id: aws.String(id),
+			MetricStat: &cloudwatchtypes.MetricStat{
+				Metric: &cloudwatchtypes.Metric{
+					Namespace:  aws.String("AWS/EC2"),
+					MetricName: aws.String(metric),
+					Dimensions: []cloudwatchtypes.Dimension{
+						{
+							Name:  aws.String("InstanceId"),
+							Value: aws.String(instanceID),
+						},
+					},
+				},
+				Period: aws.Int32(3600),
+				Stat:   aws.String(stat),
+			},
+		}
+	}
+
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		ScanBy:    cloudwatchtypes.ScanByTimestampAscending,
+		MetricDataQueries: []cloudwatchtypes.MetricDataQuery{
+			query("balance", "CPUCreditBalance", "Average"),
+			query("usage", "CPUCreditUsage", "Sum"),
+			query("surplus", "CPUSurplusCreditsCharged", "Sum"),
+		},
+	}
+
+	credits := model.CreditMetrics{InstanceID: instanceID}
+
+	paginator := cloudwatch.NewGetMetricDataPaginator(r.cw, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return model.CreditMetrics{}, fmt.Errorf("GetMetricData failed: %w", err)
+		}
+
+		for _, res := range page.MetricDataResults {
+			switch aws.ToString(res.Id) {
+			case "balance":
+				credits.Balance = append(credits.Balance, res.Values...)
+			case "usage":
+				credits.Usage = append(credits.Usage, res.Values...)
+			case "surplus":
+				credits.SurplusCharged = append(credits.SurplusCharged, res.Values...)
+			}
+		}
+	}
+
+	return credits, nil
+}
+
+// GetHourlyCpu retrieves hourly average CPU utilisation with timestamps, so
+// usage can be placed by hour of day and day of week
+func (r *RealCloudWatchClient) GetHourlyCpu(
+	ctx context.Context,
+	instanceID string,
+	days int,
+) (model.CPUHistory, error) {
+	end := time.Now().UTC().Truncate(time.Hour)
+	start := end.AddDate(0, 0, -days)
+	metricID := "cpuHourly"
+
+	input := &cloudwatch.GetMetricDataInput{
+		StartTime: aws.Time(start),
+		EndTime:   aws.Time(end),
+		ScanBy:    cloudwatchtypes.ScanByTimestampAscending,
+		MetricDataQueries: []cloudwatchtypes.MetricDataQuery{
+			{
+				Id: aws.String(metricID),
+				MetricStat: &cloudwatchtypes.MetricStat{
+					Metric: &cloudwatchtypes.Metric{
+						Namespace:  aws.String("AWS/EC2"),
+						MetricName: aws.String("CPUUtilization"),
+						Dimensions: []cloudwatchtypes.Dimension{
+							{
+								Name:  aws.String("InstanceId"),
+								Value: aws.String(instanceID),
+							},
+						},
+					},
+					Period: aws.Int32(3600),
+					Stat:   aws.String("Average"),
+				},
+			},
+		},
+	}
+
+	history := model.CPUHistory{InstanceID: instanceID}
+
+	paginator := cloudwatch.NewGetMetricDataPaginator(r.cw, input)
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return model.CPUHistory{}, fmt.Errorf("GetMetricData failed: %w", err)
+		}
+		for _, res := range page.MetricDataResults {
+			if aws.ToString(res.Id) != metricID {
+				continue
+			}
+			for i, ts := range res.Timestamps {
+				if i < len(res.Values) {
+					history.Points = append(history.Points, model.CPUPoint{Time: ts.UTC(), Value: res.Values[i]})
+				}
+			}
+		}
+	}
+
+	return history, nil
+}
+
+func sum(xs []float64) float64 {
+	total := 0.0
+	for _, v := range xs {
+		total += v
+	}
+	return total
+}
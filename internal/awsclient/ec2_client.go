@@ -11,9 +11,18 @@ import (
 
 type EC2Client interface {
 	ListInstances(ctx context.Context) ([]model.EC2Instance, error)
+	ListAutoScalingGroups(ctx context.Context, names []string) ([]model.AutoScalingGroup, error)
+	InstanceResizer
 	IsMock() bool
 }
 
+// InstanceResizer performs the stop/modify/start steps needed to change an instance type.
+type InstanceResizer interface {
+	StopInstance(ctx context.Context, instanceID string) error
+	ModifyInstanceType(ctx context.Context, instanceID string, instanceType string) error
+	StartInstance(ctx context.Context, instanceID string) error
+}
+
 type Config struct {
 	UseMock bool
 	Profile string
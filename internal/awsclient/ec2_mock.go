@@ -6,11 +6,35 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"regexp"
+	"time"
 
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
 	"github.com/PanaAnt/cloud-optimiser/internal/model"
 )
 
-type MockClient struct{}
+// mockCaptured is the day the testdata fixtures describe. Mock clients move
+// fixture timestamps forward by the time since, so ages, stop times and
+// commitment end dates come out the same whichever day the tool runs.
+var mockCaptured = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// mockStoppedAt matches the timestamp in a fixture StateTransitionReason
+var mockStoppedAt = regexp.MustCompile(`\d{4}-\d{2}-\d{2} \d{2}:\d{2}:\d{2}`)
+
+// mockTime shifts a fixture timestamp to the same offset from today
+func mockTime(t time.Time) time.Time {
+	if t.IsZero() {
+		return t
+	}
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	return t.Add(today.Sub(mockCaptured))
+}
+
+// MockClient serves instances from testdata. Type changes made through it are
+// kept in memory and reflected by ListInstances for the life of the client.
+type MockClient struct {
+	types map[string]string // instance ID -> type set by ModifyInstanceType
+}
 
 // IsMock returns true indicating mock client
 func (m *MockClient) IsMock() bool {
@@ -31,5 +55,68 @@ func (m *MockClient) ListInstances(ctx context.Context) ([]model.EC2Instance, er
 		return nil, fmt.Errorf("failed to unmarshal mock data: %w", err)
 	}
 
+	for i := range instances {
+		if t, ok := m.types[instances[i].ID]; ok {
+			instances[i].InstanceType = t
+		}
+		instances[i].LaunchTime = mockTime(instances[i].LaunchTime)
+		instances[i].StateTransitionReason = mockStoppedAt.ReplaceAllStringFunc(instances[i].StateTransitionReason, func(ts string) string {
+			t, err := time.Parse("2006-01-02 15:04:05", ts)
+			if err != nil {
+				return ts
+			}
+			return mockTime(t).Format("2006-01-02 15:04:05")
+		})
+	}
+
 	return instances, nil
 }
+
+// ListAutoScalingGroups reads mock group capacity from testdata/auto_scaling_groups.json
+func (m *MockClient) ListAutoScalingGroups(ctx context.Context, names []string) ([]model.AutoScalingGroup, error) {
+	path := filepath.Join("testdata", "auto_scaling_groups.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock Auto Scaling group data: %w", err)
+	}
+
+	var groups []model.AutoScalingGroup
+	if err := json.Unmarshal(file, &groups); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock Auto Scaling group data: %w", err)
+	}
+
+	wanted := map[string]bool{}
+	for _, name := range names {
+		wanted[name] = true
+	}
+	var out []model.AutoScalingGroup
+	for _, g := range groups {
+		if wanted[g.Name] {
+			out = append(out, g)
+		}
+	}
+	return out, nil
+}
+
+// StopInstance simulates stopping an instance
+func (m *MockClient) StopInstance(ctx context.Context, instanceID string) error {
+	logging.Debug("[mock] stopping " + instanceID)
+	return nil
+}
+
+// ModifyInstanceType simulates changing an instance type
+func (m *MockClient) ModifyInstanceType(ctx context.Context, instanceID string, instanceType string) error {
+	logging.Debug(fmt.Sprintf("[mock] modifying %s to %s", instanceID, instanceType))
+	if m.types == nil {
+		m.types = map[string]string{}
+	}
+	m.types[instanceID] = instanceType
+	return nil
+}
+
+// StartInstance simulates starting an instance
+func (m *MockClient) StartInstance(ctx context.Context, instanceID string) error {
+	logging.Debug("[mock] starting " + instanceID)
+	return nil
+}
@@ -3,16 +3,25 @@ package awsclient
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/autoscaling"
 	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
 
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
 	"github.com/PanaAnt/cloud-optimiser/internal/model"
 )
 
+// resizeWaitTimeout bounds how long we wait for an instance to stop or start
+const resizeWaitTimeout = 10 * time.Minute
+
 type RealClient struct {
 	ec2Client *ec2.Client
+	asgClient *autoscaling.Client
+	region    string
 }
 
 // NewRealClient creates a real AWS EC2 client with optional profile
@@ -29,6 +38,8 @@ func NewRealClient(ctx context.Context, profile string) (*RealClient, error) {
 
 	return &RealClient{
 		ec2Client: ec2.NewFromConfig(cfg),
+		asgClient: autoscaling.NewFromConfig(cfg),
+		region:    cfg.Region,
 	}, nil
 }
 
@@ -39,32 +50,237 @@ func (r *RealClient) IsMock() bool {
 
 // ListInstances retrieves all EC2 instances from AWS
 func (r *RealClient) ListInstances(ctx context.Context) ([]model.EC2Instance, error) {
-	input := &ec2.DescribeInstancesInput{}
+	var instances []model.EC2Instance
+	var volumeIDs []string
 
-	result, err := r.ec2Client.DescribeInstances(ctx, input)
-	if err != nil {
-		return nil, fmt.Errorf("DescribeInstances failed: %w", err)
-	}
+	paginator := ec2.NewDescribeInstancesPaginator(r.ec2Client, &ec2.DescribeInstancesInput{})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeInstances failed: %w", err)
+		}
 
-	var instances []model.EC2Instance
-	for _, res := range result.Reservations {
-		for _, inst := range res.Instances {
-			tags := map[string]string{}
-			for _, tag := range inst.Tags {
-				if tag.Key != nil && tag.Value != nil {
-					tags[*tag.Key] = *tag.Value
+		for _, res := range page.Reservations {
+			for _, inst := range res.Instances {
+				instance := r.toModel(inst, aws.ToString(res.OwnerId))
+				for _, v := range instance.Volumes {
+					volumeIDs = append(volumeIDs, v.VolumeID)
 				}
+				instances = append(instances, instance)
 			}
+		}
+	}
+
+	if err := r.describeVolumes(ctx, instances, volumeIDs); err != nil {
+		return nil, err
+	}
+	r.describeImageNames(ctx, instances)
+	return instances, nil
+}
+
+// ListAutoScalingGroups retrieves the configured capacity of the named groups
+func (r *RealClient) ListAutoScalingGroups(ctx context.Context, names []string) ([]model.AutoScalingGroup, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+
+	var groups []model.AutoScalingGroup
+	paginator := autoscaling.NewDescribeAutoScalingGroupsPaginator(r.asgClient, &autoscaling.DescribeAutoScalingGroupsInput{
+		AutoScalingGroupNames: names,
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeAutoScalingGroups failed: %w", err)
+		}
+		for _, g := range page.AutoScalingGroups {
+			groups = append(groups, model.AutoScalingGroup{
+				Name:            aws.ToString(g.AutoScalingGroupName),
+				MinSize:         aws.ToInt32(g.MinSize),
+				MaxSize:         aws.ToInt32(g.MaxSize),
+				DesiredCapacity: aws.ToInt32(g.DesiredCapacity),
+			})
+		}
+	}
+	return groups, nil
+}
+
+// describeImageNames fills in AMI names. It is best-effort: all images are
+// described in one call, and if that fails (e.g. a deregistered AMI makes
+// the whole request invalid) each image is described separately so that
+// failures only leave ImageName empty.
+func (r *RealClient) describeImageNames(ctx context.Context, instances []model.EC2Instance) {
+	seen := map[string]bool{}
+	var imageIDs []string
+	for _, inst := range instances {
+		if inst.ImageID != "" && !seen[inst.ImageID] {
+			seen[inst.ImageID] = true
+			imageIDs = append(imageIDs, inst.ImageID)
+		}
+	}
+	if len(imageIDs) == 0 {
+		return
+	}
 
-			instance := model.EC2Instance{
-				ID:           aws.ToString(inst.InstanceId),
-				InstanceType: string(inst.InstanceType),
-				State:        string(inst.State.Name),
-				Tags:         tags,
+	names := map[string]string{}
+	out, err := r.ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: imageIDs})
+	if err == nil {
+		for _, img := range out.Images {
+			names[aws.ToString(img.ImageId)] = aws.ToString(img.Name)
+		}
+	} else {
+		logging.DebugErr("DescribeImages failed, describing images one at a time", err)
+		for _, id := range imageIDs {
+			out, err := r.ec2Client.DescribeImages(ctx, &ec2.DescribeImagesInput{ImageIds: []string{id}})
+			if err != nil {
+				logging.DebugErr("DescribeImages failed for "+id, err)
+				continue
+			}
+			for _, img := range out.Images {
+				names[aws.ToString(img.ImageId)] = aws.ToString(img.Name)
 			}
+		}
+	}
 
-			instances = append(instances, instance)
+	for i := range instances {
+		instances[i].ImageName = names[instances[i].ImageID]
+	}
+}
+
+// toModel maps an EC2 API instance onto the model; volume details are filled in later
+func (r *RealClient) toModel(inst ec2types.Instance, ownerID string) model.EC2Instance {
+	tags := map[string]string{}
+	for _, tag := range inst.Tags {
+		if tag.Key != nil && tag.Value != nil {
+			tags[*tag.Key] = *tag.Value
 		}
 	}
-	return instances, nil
+
+	instance := model.EC2Instance{
+		ID:           aws.ToString(inst.InstanceId),
+		InstanceType: string(inst.InstanceType),
+		Tags:         tags,
+		AccountID:    ownerID,
+		Region:       r.region,
+		LaunchTime:   aws.ToTime(inst.LaunchTime),
+		Platform:     aws.ToString(inst.PlatformDetails),
+		Architecture: string(inst.Architecture),
+		Lifecycle:    "on-demand",
+		VpcID:        aws.ToString(inst.VpcId),
+		SubnetID:     aws.ToString(inst.SubnetId),
+		ImageID:      aws.ToString(inst.ImageId),
+
+		StateTransitionReason: aws.ToString(inst.StateTransitionReason),
+		AutoScalingGroup:      tags["aws:autoscaling:groupName"],
+	}
+
+	if inst.State != nil {
+		instance.State = string(inst.State.Name)
+	}
+	if inst.Placement != nil {
+		instance.AvailabilityZone = aws.ToString(inst.Placement.AvailabilityZone)
+		instance.Tenancy = string(inst.Placement.Tenancy)
+	}
+	if inst.InstanceLifecycle != "" {
+		instance.Lifecycle = string(inst.InstanceLifecycle)
+	}
+	if inst.IamInstanceProfile != nil {
+		instance.IAMInstanceProfile = aws.ToString(inst.IamInstanceProfile.Arn)
+	}
+
+	for _, bdm := range inst.BlockDeviceMappings {
+		if bdm.Ebs == nil || bdm.Ebs.VolumeId == nil {
+			continue
+		}
+		instance.Volumes = append(instance.Volumes, model.EBSVolume{
+			VolumeID:   *bdm.Ebs.VolumeId,
+			DeviceName: aws.ToString(bdm.DeviceName),
+		})
+	}
+
+	return instance
+}
+
+// describeVolumes fills in type and size for the volumes attached to instances
+func (r *RealClient) describeVolumes(ctx context.Context, instances []model.EC2Instance, volumeIDs []string) error {
+	if len(volumeIDs) == 0 {
+		return nil
+	}
+
+	details := map[string]ec2types.Volume{}
+	// DescribeVolumes accepts at most 500 IDs per call
+	for start := 0; start < len(volumeIDs); start += 500 {
+		end := min(start+500, len(volumeIDs))
+		paginator := ec2.NewDescribeVolumesPaginator(r.ec2Client, &ec2.DescribeVolumesInput{
+			VolumeIds: volumeIDs[start:end],
+		})
+		for paginator.HasMorePages() {
+			page, err := paginator.NextPage(ctx)
+			if err != nil {
+				return fmt.Errorf("DescribeVolumes failed: %w", err)
+			}
+			for _, v := range page.Volumes {
+				details[aws.ToString(v.VolumeId)] = v
+			}
+		}
+	}
+
+	for i := range instances {
+		for j := range instances[i].Volumes {
+			vol := &instances[i].Volumes[j]
+			v, ok := details[vol.VolumeID]
+			if !ok {
+				continue
+			}
+			vol.VolumeType = string(v.VolumeType)
+			vol.SizeGiB = aws.ToInt32(v.Size)
+			vol.Iops = aws.ToInt32(v.Iops)
+			vol.Throughput = aws.ToInt32(v.Throughput)
+		}
+	}
+	return nil
+}
+
+// StopInstance stops an instance and waits until it reaches the stopped state
+func (r *RealClient) StopInstance(ctx context.Context, instanceID string) error {
+	_, err := r.ec2Client.StopInstances(ctx, &ec2.StopInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("StopInstances failed: %w", err)
+	}
+
+	waiter := ec2.NewInstanceStoppedWaiter(r.ec2Client)
+	if err := waiter.Wait(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}}, resizeWaitTimeout); err != nil {
+		return fmt.Errorf("waiting for %s to stop: %w", instanceID, err)
+	}
+	return nil
+}
+
+// ModifyInstanceType changes the type of a stopped instance
+func (r *RealClient) ModifyInstanceType(ctx context.Context, instanceID string, instanceType string) error {
+	_, err := r.ec2Client.ModifyInstanceAttribute(ctx, &ec2.ModifyInstanceAttributeInput{
+		InstanceId:   aws.String(instanceID),
+		InstanceType: &ec2types.AttributeValue{Value: aws.String(instanceType)},
+	})
+	if err != nil {
+		return fmt.Errorf("ModifyInstanceAttribute failed: %w", err)
+	}
+	return nil
+}
+
+// StartInstance starts an instance and waits until it is running
+func (r *RealClient) StartInstance(ctx context.Context, instanceID string) error {
+	_, err := r.ec2Client.StartInstances(ctx, &ec2.StartInstancesInput{
+		InstanceIds: []string{instanceID},
+	})
+	if err != nil {
+		return fmt.Errorf("StartInstances failed: %w", err)
+	}
+
+	waiter := ec2.NewInstanceRunningWaiter(r.ec2Client)
+	if err := waiter.Wait(ctx, &ec2.DescribeInstancesInput{InstanceIds: []string{instanceID}}, resizeWaitTimeout); err != nil {
+		return fmt.Errorf("waiting for %s to start: %w", instanceID, err)
+	}
+	return nil
 }
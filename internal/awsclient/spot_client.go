@@ -0,0 +1,46 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/config"
+	"github.com/PanaAnt/cloud-optimiser/internal/logging"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+type SpotPricingClient interface {
+	GetSpotPriceHistory(ctx context.Context, instanceType, product string, days int) ([]model.SpotPrice, error)
+	IsMock() bool
+}
+
+// NewSpotPricing creates a spot price history client based on the provided configuration.
+// Returns an error if real AWS client creation fails and mock mode is not enabled.
+func NewSpotPricing(ctx context.Context, cfg Config) (SpotPricingClient, error) {
+	// Forced mock via flag
+	if cfg.UseMock {
+		logging.Debug("Spot pricing: Using MOCK (flag override)")
+		return &MockSpotPricing{}, nil
+	}
+
+	appCfg, err := config.LoadConfig()
+	if err != nil {
+		logging.Warn(fmt.Sprintf("Could not load config: %v, defaulting to mock", err))
+		return &MockSpotPricing{}, nil
+	}
+
+	if appCfg.Mode == "mock" {
+		logging.Debug("Spot pricing: Using MOCK (from config)")
+		return &MockSpotPricing{}, nil
+	}
+
+	// Attempt to create real AWS client
+	logging.Debug("Spot pricing: Attempting to create real AWS client")
+	client, err := NewRealSpotPricing(ctx, cfg.Profile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create real spot pricing client: %w", err)
+	}
+
+	logging.Debug("Spot pricing: Using REAL AWS")
+	return client, nil
+}
@@ -0,0 +1,49 @@
+package awsclient
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+type MockSpotPricing struct{}
+
+// IsMock returns true indicating mock client
+func (m *MockSpotPricing) IsMock() bool {
+	return true
+}
+
+// GetSpotPriceHistory reads mock spot prices for a product from
+// testdata/spot_prices.json. Entries without a product are Linux/UNIX.
+func (m *MockSpotPricing) GetSpotPriceHistory(ctx context.Context, instanceType, product string, days int) ([]model.SpotPrice, error) {
+	path := filepath.Join("testdata", "spot_prices.json")
+
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read mock spot price data: %w", err)
+	}
+
+	var data map[string][]model.SpotPrice
+	if err := json.Unmarshal(file, &data); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal mock spot price data: %w", err)
+	}
+
+	var prices []model.SpotPrice
+	for _, p := range data[instanceType] {
+		if p.ProductDescription == "" {
+			p.ProductDescription = "Linux/UNIX"
+		}
+		if p.ProductDescription != product {
+			continue
+		}
+		p.InstanceType = instanceType
+		prices = append(prices, p)
+	}
+
+	// Return no history if the type is not found (not an error)
+	return prices, nil
+}
@@ -0,0 +1,80 @@
+package awsclient
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	ec2types "github.com/aws/aws-sdk-go-v2/service/ec2/types"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+type RealSpotPricing struct {
+	ec2Client *ec2.Client
+}
+
+// NewRealSpotPricing creates a real AWS spot price history client with optional profile
+func NewRealSpotPricing(ctx context.Context, profile string) (*RealSpotPricing, error) {
+	opts := []func(*config.LoadOptions) error{}
+	if profile != "" {
+		opts = append(opts, config.WithSharedConfigProfile(profile))
+	}
+
+	cfg, err := config.LoadDefaultConfig(ctx, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load AWS config: %w", err)
+	}
+
+	return &RealSpotPricing{
+		ec2Client: ec2.NewFromConfig(cfg),
+	}, nil
+}
+
+// IsMock returns false indicating real AWS client
+func (r *RealSpotPricing) IsMock() bool {
+	return false
+}
+
+// GetSpotPriceHistory retrieves spot prices for an instance type and product
+// description (e.g. "Linux/UNIX", "Windows") across all AZs in the region
+// over the last days
+func (r *RealSpotPricing) GetSpotPriceHistory(ctx context.Context, instanceType, product string, days int) ([]model.SpotPrice, error) {
+	end := time.Now().UTC()
+	start := end.Add(-time.Duration(days) * 24 * time.Hour)
+
+	paginator := ec2.NewDescribeSpotPriceHistoryPaginator(r.ec2Client, &ec2.DescribeSpotPriceHistoryInput{
+		InstanceTypes:       []ec2types.InstanceType{ec2types.InstanceType(instanceType)},
+		ProductDescriptions: []string{product},
+		StartTime:           aws.Time(start),
+		EndTime:             aws.Time(end),
+	})
+
+	var prices []model.SpotPrice
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("DescribeSpotPriceHistory failed: %w", err)
+		}
+
+		for _, p := range page.SpotPriceHistory {
+			price, err := strconv.ParseFloat(aws.ToString(p.SpotPrice), 64)
+			if err != nil {
+				continue
+			}
+			prices = append(prices, model.SpotPrice{
+				InstanceType:       string(p.InstanceType),
+				AvailabilityZone:   aws.ToString(p.AvailabilityZone),
+				ProductDescription: string(p.ProductDescription),
+				Price:              price,
+				Timestamp:          aws.ToTime(p.Timestamp),
+			})
+		}
+	}
+
+	return prices, nil
+}
@@ -0,0 +1,178 @@
+package catalog
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+//go:embed instance_types.json
+var catalogJSON []byte
+
+// Family describes an instance family and how it maps to other families.
+type Family struct {
+	Architecture       string  `json:"architecture"`                  // x86_64 | arm64
+	PerformanceFactor  float64 `json:"performance_factor"`            // per-vCPU speed relative to m5 (1.0)
+	Burstable          bool    `json:"burstable"`                     // t-class CPU credit model
+	Graviton           string  `json:"graviton,omitempty"`            // arm64 equivalent family
+	PreviousGeneration bool    `json:"previous_generation,omitempty"` // superseded by Successor
+	Successor          string  `json:"successor,omitempty"`           // current-generation equivalent family
+}
+
+// InstanceType is a single catalog entry with approximate on-demand pricing.
+type InstanceType struct {
+	Name        string  `json:"-"`
+	Family      string  `json:"family"`
+	VCPU        int     `json:"vcpu"`
+	MemoryGB    float64 `json:"memory_gb"`
+	HourlyPrice float64 `json:"hourly_price"` // us-east-1 Linux on-demand, USD
+	NetworkGbps float64 `json:"network_gbps"` // burst ("up to") bandwidth
+	BaselinePct float64 `json:"baseline_pct"` // burstable baseline per vCPU, 0 for fixed performance
+	NextSmaller string  `json:"next_smaller"`
+	NextLarger  string  `json:"next_larger"`
+}
+
+// Catalog is the embedded instance type catalog.
+type Catalog struct {
+	Families      map[string]Family       `json:"families"`
+	X86OnlyImages []string                `json:"x86_only_images"`       // AMI name prefixes with no arm64 build
+	Licences      map[string]float64      `json:"licence_per_vcpu_hour"` // product description -> USD per vCPU-hour on top of Linux
+	Types         map[string]InstanceType `json:"types"`
+}
+
+var defaultCatalog = mustLoad()
+
+func mustLoad() *Catalog {
+	var c Catalog
+	if err := json.Unmarshal(catalogJSON, &c); err != nil {
+		panic(fmt.Sprintf("invalid embedded instance catalog: %v", err))
+	}
+	for name, t := range c.Types {
+		t.Name = name
+		c.Types[name] = t
+	}
+	return &c
+}
+
+// Default returns the embedded catalog.
+func Default() *Catalog {
+	return defaultCatalog
+}
+
+// Lookup returns the catalog entry for an instance type.
+func (c *Catalog) Lookup(name string) (InstanceType, bool) {
+	t, ok := c.Types[name]
+	return t, ok
+}
+
+// FamilyOf returns the family metadata for an instance type.
+func (c *Catalog) FamilyOf(name string) (Family, bool) {
+	t, ok := c.Types[name]
+	if !ok {
+		return Family{}, false
+	}
+	f, ok := c.Families[t.Family]
+	return f, ok
+}
+
+// Smaller returns the next smaller size in the same family, or "" if none.
+func (c *Catalog) Smaller(name string) string {
+	return c.Types[name].NextSmaller
+}
+
+// Larger returns the next larger size in the same family, or "" if none.
+func (c *Catalog) Larger(name string) string {
+	return c.Types[name].NextLarger
+}
+
+// GravitonEquivalent returns the same size in the family's arm64 equivalent.
+func (c *Catalog) GravitonEquivalent(name string) (InstanceType, bool) {
+	t, ok := c.Types[name]
+	if !ok {
+		return InstanceType{}, false
+	}
+	f := c.Families[t.Family]
+	if f.Graviton == "" {
+		return InstanceType{}, false
+	}
+	return c.Lookup(f.Graviton + "." + size(name))
+}
+
+// CurrentGeneration returns the same size in the successor family for
+// previous-generation types.
+func (c *Catalog) CurrentGeneration(name string) (InstanceType, bool) {
+	t, ok := c.Types[name]
+	if !ok {
+		return InstanceType{}, false
+	}
+	f := c.Families[t.Family]
+	if !f.PreviousGeneration || f.Successor == "" {
+		return InstanceType{}, false
+	}
+	return c.Lookup(f.Successor + "." + size(name))
+}
+
+// OnDemandPrice returns the approximate hourly on-demand price of a type for
+// a platform product description (e.g. "Windows"), adding the licence
+// uplift per vCPU to the Linux price.
+func (c *Catalog) OnDemandPrice(t InstanceType, product string) float64 {
+	return t.HourlyPrice + c.Licences[product]*float64(t.VCPU)
+}
+
+// CreditsPerHour is the CPU credits a burstable type earns each hour.
+// One credit is one vCPU at 100% for one minute.
+func (t InstanceType) CreditsPerHour() float64 {
+	return t.BaselinePct / 100 * float64(t.VCPU) * 60
+}
+
+// MaxCredits is the most credits a burstable type can accrue (24 hours of earnings).
+func (t InstanceType) MaxCredits() float64 {
+	return t.CreditsPerHour() * 24
+}
+
+// Performance returns the per-vCPU performance factor for a type, defaulting to 1.
+func (c *Catalog) Performance(name string) float64 {
+	f, ok := c.FamilyOf(name)
+	if !ok || f.PerformanceFactor == 0 {
+		return 1
+	}
+	return f.PerformanceFactor
+}
+
+// IsX86OnlyImage reports whether an AMI name matches a known x86-only image.
+func (c *Catalog) IsX86OnlyImage(imageName string) bool {
+	for _, prefix := range c.X86OnlyImages {
+		if strings.HasPrefix(imageName, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// sizeUnits are EC2 normalization factors used by size-flexible Reserved Instances
+var sizeUnits = map[string]float64{
+	"nano": 0.25, "micro": 0.5, "small": 1, "medium": 2, "large": 4, "xlarge": 8,
+}
+
+// NormalizationFactor returns the RI normalization factor for a type's size,
+// e.g. 4 for large and 16 for 2xlarge, or 0 if the size is unknown.
+func NormalizationFactor(name string) float64 {
+	s := size(name)
+	if u, ok := sizeUnits[s]; ok {
+		return u
+	}
+	if n, err := strconv.Atoi(strings.TrimSuffix(s, "xlarge")); err == nil && strings.HasSuffix(s, "xlarge") {
+		return float64(n) * sizeUnits["xlarge"]
+	}
+	return 0
+}
+
+// size returns the part after the family, e.g. "xlarge" for "m5.xlarge"
+func size(name string) string {
+	if i := strings.Index(name, "."); i >= 0 {
+		return name[i+1:]
+	}
+	return ""
+}
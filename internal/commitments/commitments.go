@@ -0,0 +1,469 @@
+// Package commitments matches Reserved Instances and Savings Plans to running
+// instances so that recommendations and reports account for usage that is
+// already paid for.
+package commitments
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+const hoursPerMonth = 730
+
+// ComputeFamily is the family row Compute Savings Plans are reported under
+const ComputeFamily = "compute"
+
+// savingsPlanDiscount approximates the no-upfront discount off on-demand,
+// by plan type and term in years
+var savingsPlanDiscount = map[string]map[int]float64{
+	"Compute":     {1: 0.28, 3: 0.50},
+	"EC2Instance": {1: 0.37, 3: 0.58},
+}
+
+// Discount returns the approximate discount off on-demand for a Savings Plan
+// type and term, defaulting to the 1 year rate.
+func Discount(planType string, termYears int) float64 {
+	if d, ok := savingsPlanDiscount[planType][termYears]; ok {
+		return d
+	}
+	return savingsPlanDiscount[planType][1]
+}
+
+// Input is the inventory and Cost Explorer data Analyse works from.
+// Utilisation and Coverage are optional; estimates are used without them.
+type Input struct {
+	Instances         []model.EC2Instance
+	ReservedInstances []model.ReservedInstance
+	SavingsPlans      []model.SavingsPlan
+	Utilisation       []model.CommitmentUtilisation
+	Coverage          []model.FamilyCoverage
+}
+
+// pool is the set of usage one commitment can apply to
+type pool struct {
+	key          string
+	family       string // limited to this family ("" = any)
+	instanceType string // limited to this exact type
+	az           string // limited to this AZ (zonal RIs)
+}
+
+// eligible reports whether the pool would still apply after moving to instanceType
+func (p pool) eligible(instanceType string) bool {
+	if p.instanceType != "" {
+		return instanceType == p.instanceType
+	}
+	if p.family != "" {
+		family, _, _ := strings.Cut(instanceType, ".")
+		return family == p.family
+	}
+	return true
+}
+
+// portion is the part of one instance's usage covered by one commitment
+type portion struct {
+	pool
+	id      string
+	ri      bool
+	monthly float64 // on-demand equivalent covered, USD per month
+}
+
+// usage tracks one running instance while commitments are applied
+type usage struct {
+	inst     model.EC2Instance
+	family   string
+	units    float64 // RI normalization factor
+	hourly   float64 // on-demand price
+	open     float64 // share of usage not yet covered, 0-1
+	portions []portion
+}
+
+// cover applies share of the instance's usage to a commitment
+func (u *usage) cover(p pool, id string, ri bool, share float64) {
+	u.open -= share
+	if u.open < 1e-9 {
+		u.open = 0
+	}
+	u.portions = append(u.portions, portion{pool: p, id: id, ri: ri, monthly: share * u.hourly * hoursPerMonth})
+}
+
+// Result is the outcome of matching commitments to running instances.
+type Result struct {
+	Families []model.FamilyCommitment
+	usage    map[string]*usage
+	pools    map[string]pool
+	ris      []model.ReservedInstance
+	plans    []model.SavingsPlan
+}
+
+// Analyse applies active commitments to running instances the way AWS does:
+// zonal and non-Linux RIs to exact matches, regional Linux RIs size-flexibly
+// within a family from the smallest size up, then EC2 Instance Savings Plans
+// and finally Compute Savings Plans. Unused hours per family come from Cost
+// Explorer utilisation when available and are estimated otherwise.
+func Analyse(in Input, now time.Time) *Result {
+	cat := catalog.Default()
+	r := &Result{usage: map[string]*usage{}, pools: map[string]pool{}}
+
+	var usages []*usage
+	for _, inst := range in.Instances {
+		t, ok := cat.Lookup(inst.InstanceType)
+		if inst.State != "running" || !ok {
+			continue
+		}
+		u := &usage{inst: inst, family: t.Family, units: catalog.NormalizationFactor(t.Name), hourly: t.HourlyPrice, open: 1}
+		usages = append(usages, u)
+		r.usage[inst.ID] = u
+	}
+	sort.Slice(usages, func(i, j int) bool {
+		if usages[i].units != usages[j].units {
+			return usages[i].units < usages[j].units
+		}
+		return usages[i].inst.ID < usages[j].inst.ID
+	})
+
+	util := map[string]float64{}
+	for _, u := range in.Utilisation {
+		util[u.ID] = u.UtilisationPct
+	}
+
+	families := map[string]*model.FamilyCommitment{}
+	unusedSP := map[string]float64{} // family -> unused commitment, USD per hour
+	observed := map[string][2]int{}  // family -> {with CE utilisation, total}
+	family := func(name string, id string) *model.FamilyCommitment {
+		fc, ok := families[name]
+		if !ok {
+			fc = &model.FamilyCommitment{Family: name}
+			families[name] = fc
+		}
+		o := observed[name]
+		if _, ok := util[id]; ok {
+			o[0]++
+		}
+		o[1]++
+		observed[name] = o
+		return fc
+	}
+
+	// unusedShare prefers Cost Explorer's observed utilisation over the estimate
+	unusedShare := func(id string, estimate float64) float64 {
+		if pct, ok := util[id]; ok {
+			return 1 - pct/100
+		}
+		return estimate
+	}
+
+	// Reserved Instances
+	ris := append([]model.ReservedInstance(nil), in.ReservedInstances...)
+	sort.Slice(ris, func(i, j int) bool { return ris[i].ID < ris[j].ID })
+	for _, ri := range ris {
+		if !active(ri.Start, ri.End, now) || ri.Count <= 0 {
+			continue
+		}
+		riFamily, _, _ := strings.Cut(ri.InstanceType, ".")
+		fc := family(riFamily, ri.ID)
+		hours := float64(ri.Count) * hoursPerMonth
+		fc.ReservedInstances += ri.Count
+		fc.RIHours += hours
+
+		windows := isWindows(ri.Platform)
+		var unused float64
+		if ri.AvailabilityZone == "" && !windows {
+			// Regional Linux RIs are size-flexible across the family
+			p := r.addPool(pool{key: "ri:" + riFamily, family: riFamily})
+			units := float64(ri.Count) * catalog.NormalizationFactor(ri.InstanceType)
+			left := units
+			for _, u := range usages {
+				if left <= 0 || u.open == 0 || u.family != riFamily || isWindows(u.inst.Platform) {
+					continue
+				}
+				take := math.Min(u.open*u.units, left)
+				left -= take
+				u.cover(p, ri.ID, true, take/u.units)
+			}
+			if units > 0 {
+				unused = left / units
+			}
+		} else {
+			p := r.addPool(pool{key: "ri:" + ri.InstanceType + "@" + ri.AvailabilityZone, instanceType: ri.InstanceType, az: ri.AvailabilityZone})
+			matched := 0
+			for _, u := range usages {
+				if matched == ri.Count {
+					break
+				}
+				if u.open < 1 || u.inst.InstanceType != ri.InstanceType || isWindows(u.inst.Platform) != windows {
+					continue
+				}
+				if ri.AvailabilityZone != "" && u.inst.AvailabilityZone != ri.AvailabilityZone {
+					continue
+				}
+				u.cover(p, ri.ID, true, 1)
+				matched++
+			}
+			unused = float64(ri.Count-matched) / float64(ri.Count)
+		}
+		fc.UnusedRIHours += hours * unusedShare(ri.ID, unused)
+		r.ris = append(r.ris, ri)
+	}
+
+	// Savings Plans: family-scoped EC2 Instance plans before Compute plans
+	plans := append([]model.SavingsPlan(nil), in.SavingsPlans...)
+	sort.Slice(plans, func(i, j int) bool {
+		if (plans[i].Type == "Compute") != (plans[j].Type == "Compute") {
+			return plans[j].Type == "Compute"
+		}
+		return plans[i].ID < plans[j].ID
+	})
+	for _, sp := range plans {
+		if !active(sp.Start, sp.End, now) || sp.Commitment <= 0 {
+			continue
+		}
+		p := pool{key: "sp:" + ComputeFamily}
+		name := ComputeFamily
+		if sp.Type != "Compute" {
+			p = pool{key: "sp:" + sp.InstanceFamily, family: sp.InstanceFamily}
+			name = sp.InstanceFamily
+		}
+		p = r.addPool(p)
+		fc := family(name, sp.ID)
+		fc.SavingsPlans++
+		fc.SPCommitment += sp.Commitment
+
+		rate := 1 - Discount(sp.Type, sp.TermYears)
+		budget := sp.Commitment
+		for _, u := range usages {
+			if budget <= 0 {
+				break
+			}
+			if u.open == 0 || (p.family != "" && u.family != p.family) {
+				continue
+			}
+			take := math.Min(u.open*u.hourly*rate, budget)
+			budget -= take
+			u.cover(p, sp.ID, false, take/(u.hourly*rate))
+		}
+		share := unusedShare(sp.ID, budget/sp.Commitment)
+		fc.UnusedSPHours += hoursPerMonth * share
+		unusedSP[name] += sp.Commitment * share
+		r.plans = append(r.plans, sp)
+	}
+
+	// Coverage: Cost Explorer when reported, otherwise the share matched above
+	ceCoverage := map[string]model.FamilyCoverage{}
+	for _, c := range in.Coverage {
+		ceCoverage[c.Family] = c
+	}
+	for name, fc := range families {
+		if fc.RIHours > 0 {
+			fc.RIUtilisationPct = (fc.RIHours - fc.UnusedRIHours) / fc.RIHours * 100
+		}
+		if fc.SPCommitment > 0 {
+			fc.SPUtilisationPct = (1 - unusedSP[name]/fc.SPCommitment) * 100
+		}
+
+		if c, ok := ceCoverage[name]; ok {
+			fc.RICoveragePct, fc.SPCoveragePct = c.RICoveragePct, c.SPCoveragePct
+		} else if name != ComputeFamily {
+			fc.RICoveragePct, fc.SPCoveragePct = estimatedCoverage(usages, name)
+		}
+
+		switch o := observed[name]; {
+		case o[0] == o[1]:
+			fc.Source = "cost-explorer"
+		case o[0] == 0:
+			fc.Source = "estimated"
+		default:
+			fc.Source = "mixed"
+		}
+		r.Families = append(r.Families, *fc)
+	}
+	sort.Slice(r.Families, func(i, j int) bool {
+		a, b := r.Families[i].Family, r.Families[j].Family
+		if (a == ComputeFamily) != (b == ComputeFamily) {
+			return b == ComputeFamily
+		}
+		return a < b
+	})
+
+	return r
+}
+
+// Cover returns the commitment coverage of a running instance, if any.
+func (r *Result) Cover(instanceID string) (model.CommitmentCover, bool) {
+	u := r.usage[instanceID]
+	if u == nil || len(u.portions) == 0 {
+		return model.CommitmentCover{}, false
+	}
+
+	cover := model.CommitmentCover{CoveredPct: (1 - u.open) * 100}
+	seen := map[string]bool{}
+	for _, p := range u.portions {
+		if seen[p.id] {
+			continue
+		}
+		seen[p.id] = true
+		if p.ri {
+			cover.RIs = append(cover.RIs, p.id)
+		} else {
+			cover.SavingsPlans = append(cover.SavingsPlans, p.id)
+		}
+	}
+	return cover, true
+}
+
+// retainedShare returns the share of an instance's current usage that its
+// commitments would still cover after moving it to target
+func (r *Result) retainedShare(instanceID, target string) float64 {
+	u := r.usage[instanceID]
+	if u == nil || u.hourly == 0 {
+		return 0
+	}
+	kept := 0.0
+	for _, p := range u.portions {
+		if p.eligible(target) {
+			kept += p.monthly
+		}
+	}
+	return kept / (u.hourly * hoursPerMonth)
+}
+
+// Mark records commitment coverage on recommendations and reduces savings
+// that would only free commitment nothing else can absorb. Commitment freed
+// by a change is absorbed by uncovered on-demand usage the same commitment
+// could apply to; the rest is still billed and no longer saves anything.
+func (r *Result) Mark(recs []model.Recommendation) {
+	spill := r.spillover()
+
+	for i := range recs {
+		rec := &recs[i]
+		cover, ok := r.Cover(rec.InstanceID)
+		if !ok {
+			continue
+		}
+		rec.Commitment = &cover
+
+		u := r.usage[rec.InstanceID]
+		onDemand := u.hourly * hoursPerMonth
+		if rec.EstimatedSaving <= 0 || rec.MonthlyCost <= 0 || onDemand == 0 {
+			continue
+		}
+
+		target := rec.SuggestedType
+		if target == "" {
+			target = rec.InstanceType
+		}
+
+		// Usage left after the change fills eligible commitments first
+		left := onDemand * (1 - math.Min(1, rec.EstimatedSaving/rec.MonthlyCost))
+		lost := 0.0
+		for _, p := range u.portions {
+			kept := 0.0
+			if p.eligible(target) {
+				kept = math.Min(p.monthly, left)
+				left -= kept
+			}
+			freed := p.monthly - kept
+			// The instance's own uncovered usage moves with it, so it cannot absorb anything
+			own := 0.0
+			if p.eligible(rec.InstanceType) {
+				own = u.open * onDemand
+			}
+			absorbed := math.Max(0, math.Min(freed, spill[p.key]-own))
+			spill[p.key] -= absorbed
+			lost += freed - absorbed
+		}
+		// Express the loss on the same basis as the billed cost
+		lost *= rec.MonthlyCost / onDemand
+		if lost < 0.005 {
+			continue
+		}
+
+		saving := rec.EstimatedSaving - lost
+		note := fmt.Sprintf(" %.0f%% of its usage is covered by %s; $%.2f/mo of commitment would be freed with nothing else to absorb it",
+			cover.CoveredPct, describeCover(cover), lost)
+		if saving > 0 {
+			note += fmt.Sprintf(", cutting the saving to $%.2f/mo.", saving)
+		} else {
+			note += fmt.Sprintf(", so this saves nothing (net +$%.2f/mo) until the commitment ends.", -saving)
+			saving = 0
+		}
+		rec.EstimatedSaving = saving
+		rec.Reason += note
+	}
+}
+
+// spillover returns, per pool, the uncovered on-demand usage (USD per month)
+// that freed commitment from that pool could move onto
+func (r *Result) spillover() map[string]float64 {
+	spill := map[string]float64{}
+	for key, p := range r.pools {
+		for _, u := range r.usage {
+			if u.open == 0 || !p.eligible(u.inst.InstanceType) {
+				continue
+			}
+			if p.az != "" && u.inst.AvailabilityZone != p.az {
+				continue
+			}
+			spill[key] += u.open * u.hourly * hoursPerMonth
+		}
+	}
+	return spill
+}
+
+// addPool registers a pool so its spillover can be computed
+func (r *Result) addPool(p pool) pool {
+	r.pools[p.key] = p
+	return p
+}
+
+// estimatedCoverage returns the RI and Savings Plan share of a family's running on-demand usage
+func estimatedCoverage(usages []*usage, family string) (float64, float64) {
+	var total, ri, sp float64
+	for _, u := range usages {
+		if u.family != family {
+			continue
+		}
+		total += u.hourly * hoursPerMonth
+		for _, p := range u.portions {
+			if p.ri {
+				ri += p.monthly
+			} else {
+				sp += p.monthly
+			}
+		}
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return ri / total * 100, sp / total * 100
+}
+
+// describeCover lists the commitments covering an instance for a reason
+func describeCover(c model.CommitmentCover) string {
+	var parts []string
+	if n := len(c.RIs); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d Reserved Instance(s)", n))
+	}
+	if n := len(c.SavingsPlans); n > 0 {
+		parts = append(parts, fmt.Sprintf("%d Savings Plan(s)", n))
+	}
+	return strings.Join(parts, " and ")
+}
+
+// active reports whether a commitment term includes now; a zero end is open-ended
+func active(start, end, now time.Time) bool {
+	if !start.IsZero() && now.Before(start) {
+		return false
+	}
+	return end.IsZero() || now.Before(end)
+}
+
+// isWindows reports whether an instance platform or RI product description is Windows
+func isWindows(platform string) bool {
+	return strings.Contains(strings.ToLower(platform), "windows")
+}
@@ -0,0 +1,200 @@
+package commitments
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+var testNow = time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+
+// running returns a running Linux instance in eu-west-2a
+func running(id, instanceType string) model.EC2Instance {
+	return model.EC2Instance{ID: id, InstanceType: instanceType, State: "running", AvailabilityZone: "eu-west-2a"}
+}
+
+// ri returns a one-year Linux Reserved Instance active at testNow
+func ri(id, instanceType string, count int, az string) model.ReservedInstance {
+	return model.ReservedInstance{
+		ID: id, InstanceType: instanceType, Count: count, AvailabilityZone: az, Platform: "Linux/UNIX",
+		Start: testNow.AddDate(0, -6, 0), End: testNow.AddDate(0, 6, 0),
+	}
+}
+
+// hourly returns the catalog on-demand price of an instance type
+func hourly(t *testing.T, instanceType string) float64 {
+	t.Helper()
+	it, ok := catalog.Default().Lookup(instanceType)
+	if !ok {
+		t.Fatalf("%s not in catalog", instanceType)
+	}
+	return it.HourlyPrice
+}
+
+func TestAnalyse(t *testing.T) {
+	future := ri("ri-future", "m5.large", 1, "")
+	future.Start = testNow.AddDate(0, 1, 0)
+	windows := running("i-win", "m5.large")
+	windows.Platform = "windows"
+	m5Large := hourly(t, "m5.large")
+
+	tests := []struct {
+		name        string
+		in          Input
+		wantCovered map[string]float64 // instance ID -> covered percent
+		family      string
+		wantUtil    float64
+		wantSource  string
+	}{
+		{
+			name:        "regional RI is size-flexible within the family",
+			in:          Input{Instances: []model.EC2Instance{running("i-a", "m5.large"), running("i-b", "m5.large")}, ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.xlarge", 1, "")}},
+			wantCovered: map[string]float64{"i-a": 100, "i-b": 100},
+			family:      "m5", wantUtil: 100, wantSource: "estimated",
+		},
+		{
+			name:        "smaller regional RI covers part of a larger instance",
+			in:          Input{Instances: []model.EC2Instance{running("i-a", "m5.xlarge")}, ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.large", 1, "")}},
+			wantCovered: map[string]float64{"i-a": 50},
+			family:      "m5", wantUtil: 100, wantSource: "estimated",
+		},
+		{
+			name:        "zonal RI only matches its zone",
+			in:          Input{Instances: []model.EC2Instance{running("i-a", "m5.large")}, ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.large", 1, "eu-west-2b")}},
+			wantCovered: map[string]float64{},
+			family:      "m5", wantUtil: 0, wantSource: "estimated",
+		},
+		{
+			name:        "Linux RI does not cover Windows",
+			in:          Input{Instances: []model.EC2Instance{windows}, ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.large", 1, "")}},
+			wantCovered: map[string]float64{},
+			family:      "m5", wantUtil: 0, wantSource: "estimated",
+		},
+		{
+			name:        "RI not yet started is ignored",
+			in:          Input{Instances: []model.EC2Instance{running("i-a", "m5.large")}, ReservedInstances: []model.ReservedInstance{future}},
+			wantCovered: map[string]float64{},
+		},
+		{
+			name: "Cost Explorer utilisation replaces the estimate",
+			in: Input{
+				Instances:         []model.EC2Instance{running("i-a", "m5.large")},
+				ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.large", 1, "")},
+				Utilisation:       []model.CommitmentUtilisation{{ID: "ri-1", UtilisationPct: 80}},
+			},
+			wantCovered: map[string]float64{"i-a": 100},
+			family:      "m5", wantUtil: 80, wantSource: "cost-explorer",
+		},
+		{
+			name: "Compute plan covers its commitment at the discounted rate",
+			in: Input{
+				Instances:    []model.EC2Instance{running("i-a", "m5.large")},
+				SavingsPlans: []model.SavingsPlan{{ID: "sp-1", Type: "Compute", Commitment: m5Large * (1 - 0.28) / 4, TermYears: 1}},
+			},
+			wantCovered: map[string]float64{"i-a": 25},
+			family:      ComputeFamily, wantUtil: 100, wantSource: "estimated",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Analyse(tt.in, testNow)
+
+			for _, inst := range tt.in.Instances {
+				got := 0.0
+				if c, ok := res.Cover(inst.ID); ok {
+					got = c.CoveredPct
+				}
+				if math.Abs(got-tt.wantCovered[inst.ID]) > 1e-6 {
+					t.Errorf("%s covered %.2f%%, want %.2f%%", inst.ID, got, tt.wantCovered[inst.ID])
+				}
+			}
+
+			if tt.family == "" {
+				if len(res.Families) != 0 {
+					t.Errorf("families = %+v, want none", res.Families)
+				}
+				return
+			}
+			var fc *model.FamilyCommitment
+			for i := range res.Families {
+				if res.Families[i].Family == tt.family {
+					fc = &res.Families[i]
+				}
+			}
+			if fc == nil {
+				t.Fatalf("no %s family in %+v", tt.family, res.Families)
+			}
+			util := fc.RIUtilisationPct
+			if tt.family == ComputeFamily {
+				util = fc.SPUtilisationPct
+			}
+			if math.Abs(util-tt.wantUtil) > 1e-6 || fc.Source != tt.wantSource {
+				t.Errorf("%s utilisation %.2f%% from %s, want %.2f%% from %s", tt.family, util, fc.Source, tt.wantUtil, tt.wantSource)
+			}
+		})
+	}
+}
+
+func TestMark(t *testing.T) {
+	monthly := hourly(t, "m5.large") * hoursPerMonth
+	downsize := func(id string) model.Recommendation {
+		return model.Recommendation{
+			InstanceID: id, InstanceType: "m5.large", State: "running", MonthlyCost: monthly,
+			Action: "Downsize", SuggestedType: "t3.large", EstimatedSaving: monthly * 0.3,
+		}
+	}
+
+	tests := []struct {
+		name       string
+		instances  []model.EC2Instance
+		rec        model.Recommendation
+		wantSaving float64
+		wantCover  bool
+		wantReason string
+	}{
+		{
+			name:       "freed RI with nothing to absorb it saves nothing",
+			instances:  []model.EC2Instance{running("i-a", "m5.large")},
+			rec:        downsize("i-a"),
+			wantSaving: 0,
+			wantCover:  true,
+			wantReason: "so this saves nothing",
+		},
+		{
+			name:       "uncovered usage in the family absorbs the freed RI",
+			instances:  []model.EC2Instance{running("i-a", "m5.large"), running("i-b", "m5.large")},
+			rec:        downsize("i-a"),
+			wantSaving: monthly * 0.3,
+			wantCover:  true,
+		},
+		{
+			name:       "uncovered instance keeps its saving",
+			instances:  []model.EC2Instance{running("i-a", "m5.large"), running("i-b", "m5.large")},
+			rec:        downsize("i-b"),
+			wantSaving: monthly * 0.3,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res := Analyse(Input{Instances: tt.instances, ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.large", 1, "")}}, testNow)
+			recs := []model.Recommendation{tt.rec}
+			res.Mark(recs)
+
+			if math.Abs(recs[0].EstimatedSaving-tt.wantSaving) > 1e-6 {
+				t.Errorf("saving = %.2f, want %.2f", recs[0].EstimatedSaving, tt.wantSaving)
+			}
+			if (recs[0].Commitment != nil) != tt.wantCover {
+				t.Errorf("commitment = %+v, want covered %v", recs[0].Commitment, tt.wantCover)
+			}
+			if !strings.Contains(recs[0].Reason, tt.wantReason) {
+				t.Errorf("reason %q does not contain %q", recs[0].Reason, tt.wantReason)
+			}
+		})
+	}
+}
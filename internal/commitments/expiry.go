@@ -0,0 +1,82 @@
+package commitments
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Expiring returns the active commitments that end within the given number of
+// days, soonest first. The increase is what the usage each one covers today
+// costs on demand, less what the commitment itself costs; usage another
+// commitment would pick up after expiry is not taken into account.
+func (r *Result) Expiring(now time.Time, withinDays int) []model.CommitmentExpiry {
+	horizon := now.AddDate(0, 0, withinDays)
+	soon := func(end time.Time) bool {
+		return !end.IsZero() && !end.After(horizon)
+	}
+
+	var out []model.CommitmentExpiry
+	for _, ri := range r.ris {
+		if !soon(ri.End) {
+			continue
+		}
+		hourly := ri.HourlyPrice
+		if term := ri.End.Sub(ri.Start).Hours(); ri.FixedPrice > 0 && term > 0 {
+			hourly += ri.FixedPrice / term // amortise the upfront payment over the term
+		}
+		desc := fmt.Sprintf("%dx %s", ri.Count, ri.InstanceType)
+		if ri.AvailabilityZone != "" {
+			desc += " in " + ri.AvailabilityZone
+		}
+		out = append(out, r.expiry(ri.ID, "reserved-instance", desc, ri.End, now, float64(ri.Count)*hourly*hoursPerMonth))
+	}
+	for _, sp := range r.plans {
+		if !soon(sp.End) {
+			continue
+		}
+		desc := fmt.Sprintf("%s %dy $%.3f/h", sp.Type, sp.TermYears, sp.Commitment)
+		if sp.InstanceFamily != "" {
+			desc = fmt.Sprintf("%s %s %dy $%.3f/h", sp.Type, sp.InstanceFamily, sp.TermYears, sp.Commitment)
+		}
+		out = append(out, r.expiry(sp.ID, "savings-plan", desc, sp.End, now, sp.Commitment*hoursPerMonth))
+	}
+
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].End.Equal(out[j].End) {
+			return out[i].End.Before(out[j].End)
+		}
+		return out[i].ID < out[j].ID
+	})
+	return out
+}
+
+// expiry totals the usage one commitment covers across running instances
+func (r *Result) expiry(id, kind, desc string, end, now time.Time, cost float64) model.CommitmentExpiry {
+	e := model.CommitmentExpiry{
+		ID:             id,
+		Kind:           kind,
+		Description:    desc,
+		End:            end,
+		DaysLeft:       int(math.Ceil(end.Sub(now).Hours() / 24)),
+		CommitmentCost: cost,
+	}
+	for instanceID, u := range r.usage {
+		covered := 0.0
+		for _, p := range u.portions {
+			if p.id == id {
+				covered += p.monthly
+			}
+		}
+		if covered > 0 {
+			e.Instances = append(e.Instances, instanceID)
+			e.CoveredOnDemand += covered
+		}
+	}
+	sort.Strings(e.Instances)
+	e.MonthlyIncrease = e.CoveredOnDemand - e.CommitmentCost
+	return e
+}
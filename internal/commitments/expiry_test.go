@@ -0,0 +1,78 @@
+package commitments
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func TestExpiring(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	days := func(n int) time.Time { return now.AddDate(0, 0, n) }
+
+	in := Input{
+		Instances: []model.EC2Instance{
+			{ID: "i-m5", InstanceType: "m5.large", State: "running"},
+		},
+		ReservedInstances: []model.ReservedInstance{
+			{ID: "ri-soon", InstanceType: "m5.large", Count: 1, Platform: "Linux/UNIX", HourlyPrice: 0.05, Start: days(-300), End: days(13)},
+			{ID: "ri-edge", InstanceType: "r4.large", Count: 1, Platform: "Linux/UNIX", FixedPrice: 876, HourlyPrice: 0.02, Start: days(-305), End: days(60)},
+			{ID: "ri-later", InstanceType: "c5.large", Count: 1, Platform: "Linux/UNIX", HourlyPrice: 0.04, Start: days(-10), End: days(61)},
+			{ID: "ri-ended", InstanceType: "m5.large", Count: 1, Platform: "Linux/UNIX", HourlyPrice: 0.05, Start: days(-400), End: days(-1)},
+		},
+		SavingsPlans: []model.SavingsPlan{
+			{ID: "sp-compute", Type: "Compute", Commitment: 0.01, TermYears: 1, Start: days(-340), End: days(25)},
+		},
+	}
+	res := Analyse(in, now)
+
+	got := res.Expiring(now, 60)
+	var ids []string
+	for _, e := range got {
+		ids = append(ids, e.ID)
+	}
+	if want := "ri-soon,sp-compute,ri-edge"; strings.Join(ids, ",") != want {
+		t.Fatalf("Expiring = %v, want %s (soonest first, horizon inclusive, ended left out)", ids, want)
+	}
+
+	m5, _ := catalog.Default().Lookup("m5.large")
+	tests := []struct {
+		id        string
+		daysLeft  int
+		instances string
+		covered   float64
+		cost      float64
+	}{
+		// The RI covers the whole m5.large, so the Compute plan has nothing left
+		{"ri-soon", 13, "i-m5", m5.HourlyPrice * hoursPerMonth, 0.05 * hoursPerMonth},
+		{"sp-compute", 25, "", 0, 0.01 * hoursPerMonth},
+		// The upfront payment is spread over the term: 876 / 8760h = 0.10/h
+		{"ri-edge", 60, "", 0, (0.02 + 0.10) * hoursPerMonth},
+	}
+	for i, tt := range tests {
+		e := got[i]
+		if e.ID != tt.id {
+			t.Fatalf("entry %d is %s, want %s", i, e.ID, tt.id)
+		}
+		if e.DaysLeft != tt.daysLeft {
+			t.Errorf("%s: days left = %d, want %d", tt.id, e.DaysLeft, tt.daysLeft)
+		}
+		if strings.Join(e.Instances, ",") != tt.instances {
+			t.Errorf("%s: covers %v, want %q", tt.id, e.Instances, tt.instances)
+		}
+		if math.Abs(e.CoveredOnDemand-tt.covered) > 1e-6 || math.Abs(e.CommitmentCost-tt.cost) > 1e-6 {
+			t.Errorf("%s: on-demand %.4f cost %.4f, want %.4f and %.4f", tt.id, e.CoveredOnDemand, e.CommitmentCost, tt.covered, tt.cost)
+		}
+		if math.Abs(e.MonthlyIncrease-(tt.covered-tt.cost)) > 1e-6 {
+			t.Errorf("%s: increase = %.4f, want %.4f", tt.id, e.MonthlyIncrease, tt.covered-tt.cost)
+		}
+	}
+
+	if got := res.Expiring(now, 10); len(got) != 0 {
+		t.Errorf("Expiring within 10 days = %+v, want none", got)
+	}
+}
@@ -0,0 +1,159 @@
+package commitments
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// baselinePercentile picks the steady-state level from daily on-demand cost:
+// spend at or above it on nine days out of ten
+const baselinePercentile = 10
+
+// minBaseline is the smallest hourly on-demand baseline worth a commitment
+const minBaseline = 0.01
+
+// terms are the Savings Plan terms proposed, in years
+var terms = []int{1, 3}
+
+// Baselines returns the steady-state on-demand spend per family. The
+// historical level is a low percentile of daily on-demand cost; the
+// recommendations then move spend out of (and into) families. res may be nil
+// when no commitments were loaded.
+func Baselines(history []model.FamilyDailyCost, recs []model.Recommendation, res *Result) []model.FamilyBaseline {
+	byFamily := map[string]*model.FamilyBaseline{}
+	entry := func(family string) *model.FamilyBaseline {
+		b, ok := byFamily[family]
+		if !ok {
+			b = &model.FamilyBaseline{Family: family}
+			byFamily[family] = b
+		}
+		return b
+	}
+
+	for _, h := range history {
+		if len(h.Daily) == 0 {
+			continue
+		}
+		entry(h.Family).Historical = percentile(h.Daily, baselinePercentile) / 24
+	}
+
+	for _, rec := range recs {
+		if rec.State != "running" || rec.EstimatedSaving <= 0 || rec.MonthlyCost <= 0 {
+			continue
+		}
+
+		// Only the uncovered share of an instance shows up as on-demand spend,
+		// and commitments that still apply to the new type keep covering it
+		covered, retained := 0.0, 0.0
+		if res != nil {
+			if c, ok := res.Cover(rec.InstanceID); ok {
+				covered = c.CoveredPct / 100
+			}
+			retained = res.retainedShare(rec.InstanceID, target(rec))
+		}
+		current := rec.MonthlyCost / hoursPerMonth
+		after := math.Max(0, current-rec.EstimatedSaving/hoursPerMonth)
+		before := current * (1 - covered)
+		onDemand := math.Max(0, after-current*retained)
+
+		from, to := family(rec.InstanceType), family(target(rec))
+		entry(from).RightSizing -= before
+		entry(to).RightSizing += onDemand
+	}
+
+	var out []model.FamilyBaseline
+	for _, b := range byFamily {
+		if b.Historical == 0 && math.Abs(b.RightSizing) < 1e-9 {
+			continue
+		}
+		b.BaselineRate = math.Max(0, b.Historical+b.RightSizing)
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Family < out[j].Family })
+	return out
+}
+
+// PurchaseOptions proposes Savings Plans that cover the baseline: one Compute
+// plan across all families and one EC2 Instance plan per family, for each
+// term. The commitment is the baseline at the plan's discounted rate. Each
+// plan type and term is an alternative for the same usage, so only the
+// options within one Alternative add up.
+func PurchaseOptions(baselines []model.FamilyBaseline) []model.SavingsPlanOption {
+	total := 0.0
+	for _, b := range baselines {
+		if b.BaselineRate >= minBaseline {
+			total += b.BaselineRate
+		}
+	}
+
+	var out []model.SavingsPlanOption
+	for _, years := range terms {
+		if total >= minBaseline {
+			out = append(out, option("Compute", "", years, total))
+		}
+	}
+	for _, years := range terms {
+		for _, b := range baselines {
+			if b.BaselineRate >= minBaseline {
+				out = append(out, option("EC2Instance", b.Family, years, b.BaselineRate))
+			}
+		}
+	}
+	return out
+}
+
+// option prices one Savings Plan covering onDemand USD per hour of usage.
+// With no upfront payment the commitment is owed every hour of the term, so
+// the plan only beats on-demand if the usage it covers lasts at least
+// (1 - discount) of the term, or never falls below (1 - discount) of today.
+func option(planType, family string, years int, onDemand float64) model.SavingsPlanOption {
+	d := Discount(planType, years)
+	commitment := onDemand * (1 - d)
+	months := float64(years * 12)
+
+	return model.SavingsPlanOption{
+		Alternative:       fmt.Sprintf("%s-%dy", planType, years),
+		Type:              planType,
+		Family:            family,
+		TermYears:         years,
+		DiscountPct:       d * 100,
+		HourlyCommitment:  commitment,
+		MonthlyCommitment: commitment * hoursPerMonth,
+		MonthlySaving:     (onDemand - commitment) * hoursPerMonth,
+		TermSaving:        (onDemand - commitment) * hoursPerMonth * months,
+		BreakEvenMonth:    months * (1 - d),
+		BreakEvenUsagePct: (1 - d) * 100,
+	}
+}
+
+// target returns the type an instance runs as after its recommendation
+func target(rec model.Recommendation) string {
+	if rec.SuggestedType != "" {
+		return rec.SuggestedType
+	}
+	return rec.InstanceType
+}
+
+// family returns the family part of an instance type
+func family(instanceType string) string {
+	f, _, _ := strings.Cut(instanceType, ".")
+	return f
+}
+
+// percentile returns the nearest-rank percentile of xs
+func percentile(xs []float64, p float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), xs...)
+	sort.Float64s(sorted)
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,91 @@
+package commitments
+
+import (
+	"math"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func TestBaselines(t *testing.T) {
+	// Ten days of m5 spend: the 10th percentile is the lowest day, $24 or $1/h
+	history := []model.FamilyDailyCost{
+		{Family: "m5", Daily: []float64{48, 40, 48, 48, 24, 48, 48, 48, 48, 48}},
+		{Family: "c5", Daily: []float64{}},
+	}
+
+	monthly := 0.096 * hoursPerMonth
+	moved := model.Recommendation{
+		InstanceID: "i-a", InstanceType: "m5.large", State: "running", MonthlyCost: monthly,
+		SuggestedType: "c6i.large", EstimatedSaving: monthly * 0.25,
+	}
+	kept := model.Recommendation{InstanceID: "i-b", InstanceType: "m5.large", State: "stopped", MonthlyCost: monthly, EstimatedSaving: monthly}
+
+	got := Baselines(history, []model.Recommendation{moved, kept}, nil)
+	want := map[string]model.FamilyBaseline{
+		"c6i": {Family: "c6i", RightSizing: 0.072, BaselineRate: 0.072},
+		"m5":  {Family: "m5", Historical: 1, RightSizing: -0.096, BaselineRate: 0.904},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("Baselines = %+v, want %d families", got, len(want))
+	}
+	for _, b := range got {
+		w := want[b.Family]
+		if math.Abs(b.Historical-w.Historical) > 1e-9 || math.Abs(b.RightSizing-w.RightSizing) > 1e-9 || math.Abs(b.BaselineRate-w.BaselineRate) > 1e-9 {
+			t.Errorf("%s baseline = %+v, want %+v", b.Family, b, w)
+		}
+	}
+
+	// A fully covered instance moving within the RI's reach adds no on-demand spend
+	res := Analyse(Input{Instances: []model.EC2Instance{running("i-a", "m5.large")}, ReservedInstances: []model.ReservedInstance{ri("ri-1", "m5.large", 1, "")}}, testNow)
+	within := moved
+	within.SuggestedType = "m5.large"
+	for _, b := range Baselines(nil, []model.Recommendation{within}, res) {
+		if math.Abs(b.RightSizing) > 1e-9 {
+			t.Errorf("covered move changed %s baseline by %.4f/h", b.Family, b.RightSizing)
+		}
+	}
+}
+
+func TestPurchaseOptions(t *testing.T) {
+	baselines := []model.FamilyBaseline{
+		{Family: "m6i", BaselineRate: 1},
+		{Family: "t3", BaselineRate: 0.5},
+		{Family: "r5", BaselineRate: 0.005}, // below minBaseline
+	}
+
+	got := PurchaseOptions(baselines)
+	want := []struct {
+		alternative string
+		family      string
+		commitment  float64
+		saving      float64
+		breakEven   float64
+	}{
+		{"Compute-1y", "", 1.5 * 0.72, 1.5 * 0.28 * hoursPerMonth, 12 * 0.72},
+		{"Compute-3y", "", 1.5 * 0.50, 1.5 * 0.50 * hoursPerMonth, 36 * 0.50},
+		{"EC2Instance-1y", "m6i", 1 * 0.63, 1 * 0.37 * hoursPerMonth, 12 * 0.63},
+		{"EC2Instance-1y", "t3", 0.5 * 0.63, 0.5 * 0.37 * hoursPerMonth, 12 * 0.63},
+		{"EC2Instance-3y", "m6i", 1 * 0.42, 1 * 0.58 * hoursPerMonth, 36 * 0.42},
+		{"EC2Instance-3y", "t3", 0.5 * 0.42, 0.5 * 0.58 * hoursPerMonth, 36 * 0.42},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("PurchaseOptions returned %d options, want %d: %+v", len(got), len(want), got)
+	}
+	for i, w := range want {
+		o := got[i]
+		if o.Alternative != w.alternative || o.Family != w.family {
+			t.Errorf("option %d = %s %s, want %s %s", i, o.Alternative, o.Family, w.alternative, w.family)
+		}
+		if math.Abs(o.HourlyCommitment-w.commitment) > 1e-9 || math.Abs(o.MonthlySaving-w.saving) > 1e-9 {
+			t.Errorf("%s %s: commit %.4f/h saving %.2f/mo, want %.4f/h and %.2f/mo", w.alternative, w.family, o.HourlyCommitment, o.MonthlySaving, w.commitment, w.saving)
+		}
+		if math.Abs(o.BreakEvenMonth-w.breakEven) > 1e-9 || math.Abs(o.BreakEvenUsagePct-w.breakEven/float64(o.TermYears*12)*100) > 1e-9 {
+			t.Errorf("%s %s: break-even month %.2f at %.0f%%, want month %.2f", w.alternative, w.family, o.BreakEvenMonth, o.BreakEvenUsagePct, w.breakEven)
+		}
+	}
+
+	if got := PurchaseOptions([]model.FamilyBaseline{{Family: "r5", BaselineRate: 0.005}}); len(got) != 0 {
+		t.Errorf("PurchaseOptions below the minimum = %+v, want none", got)
+	}
+}
@@ -7,14 +7,15 @@ import (
 )
 
 type AppConfig struct {
-	Mode string `json:"mode"` 
+	Mode string `json:"mode"`
 }
 
 var defaultConfig = AppConfig{
 	Mode: "mock",
 }
 
-func getConfigPath() (string, error) {
+// Dir returns the application data directory, creating it if needed.
+func Dir() (string, error) {
 	home, err := os.UserHomeDir()
 	if err != nil {
 		return "", err
@@ -25,9 +26,17 @@ func getConfigPath() (string, error) {
 		os.MkdirAll(dir, 0700)
 	}
 
-	return filepath.Join(dir, "config.json"), nil
+	return dir, nil
 }
 
+func getConfigPath() (string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(dir, "config.json"), nil
+}
 
 func LoadConfig() (AppConfig, error) {
 	path, err := getConfigPath()
@@ -37,7 +46,7 @@ func LoadConfig() (AppConfig, error) {
 
 	_, err = os.Stat(path)
 	if os.IsNotExist(err) {
-		
+
 		err = SaveConfig(defaultConfig)
 		return defaultConfig, err
 	}
@@ -68,4 +77,3 @@ func SaveConfig(cfg AppConfig) error {
 
 	return os.WriteFile(path, bytes, 0600)
 }
-
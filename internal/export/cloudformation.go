@@ -0,0 +1,350 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Tags CloudFormation adds to every resource it creates
+const (
+	stackNameTag = "aws:cloudformation:stack-name"
+	logicalIDTag = "aws:cloudformation:logical-id"
+)
+
+// ParameterChange is a stack parameter override that applies a recommendation.
+type ParameterChange struct {
+	ParameterKey  string   `json:"parameter_key"`
+	CurrentValue  string   `json:"current_value"`
+	ProposedValue string   `json:"proposed_value"`
+	InstanceIDs   []string `json:"instance_ids"`
+}
+
+// ResourceChange is a template property edit that applies a recommendation.
+type ResourceChange struct {
+	LogicalID     string   `json:"logical_id"`
+	ResourceType  string   `json:"resource_type"`
+	PropertyPath  string   `json:"property_path"`
+	CurrentValue  string   `json:"current_value"`
+	ProposedValue string   `json:"proposed_value"`
+	InstanceIDs   []string `json:"instance_ids"`
+}
+
+// StackChangeSet groups the proposed changes for one stack.
+type StackChangeSet struct {
+	StackName       string            `json:"stack_name"`
+	Parameters      []ParameterChange `json:"parameters,omitempty"`
+	ResourceChanges []ResourceChange  `json:"resource_changes,omitempty"`
+}
+
+// CloudFormationExport is the result of mapping recommendations to stacks.
+type CloudFormationExport struct {
+	Stacks   []StackChangeSet `json:"stacks"`
+	Unmapped []Unmapped       `json:"unmapped,omitempty"`
+}
+
+// cfnTemplate is the subset of a JSON template needed to locate InstanceType
+type cfnTemplate struct {
+	Parameters map[string]json.RawMessage `json:"Parameters"`
+	Resources  map[string]struct {
+		Type       string                     `json:"Type"`
+		Properties map[string]json.RawMessage `json:"Properties"`
+	} `json:"Resources"`
+}
+
+// cfnTarget is where a stack declares an instance's type
+type cfnTarget struct {
+	parameter    string
+	logicalID    string
+	resourceType string
+	propertyPath string
+}
+
+// PlanCloudFormation maps type-changing recommendations to CloudFormation
+// stacks using the stack-name and logical-id tags on each instance.
+//
+// If templateDir holds a synthesized JSON template for a stack
+// (<stack>.template.json as written by `cdk synth`, or <stack>.json),
+// it is used to tell parameter-driven types from literal properties and
+// to follow Auto Scaling groups to their launch template.
+func PlanCloudFormation(
+	recs []model.Recommendation,
+	instances map[string]model.EC2Instance,
+	templateDir string,
+) (CloudFormationExport, error) {
+	var out CloudFormationExport
+
+	templates := map[string]*cfnTemplate{}
+	stacks := map[string]*StackChangeSet{}
+	var stackOrder []string
+
+	// Several instances (e.g. an Auto Scaling group) can map to one target
+	type pending struct {
+		stack  string
+		target cfnTarget
+		recs   []model.Recommendation
+	}
+	targets := map[string]*pending{}
+	var targetOrder []string
+
+	changes, unmapped := typeChanges(recs)
+	out.Unmapped = append(out.Unmapped, unmapped...)
+	for _, r := range changes {
+		inst := instances[r.InstanceID]
+		stack := inst.Tags[stackNameTag]
+		logicalID := inst.Tags[logicalIDTag]
+		if stack == "" || logicalID == "" {
+			out.Unmapped = append(out.Unmapped, Unmapped{r.InstanceID, r.SuggestedType, "instance has no CloudFormation stack tags"})
+			continue
+		}
+
+		tmpl, ok := templates[stack]
+		if !ok && templateDir != "" {
+			t, err := loadCfnTemplate(templateDir, stack)
+			if err != nil {
+				return out, err
+			}
+			tmpl = t
+			templates[stack] = t
+		}
+
+		// Without a template an ASG member's logical ID names the group, and
+		// the type lives in a launch template that cannot be found
+		if tmpl == nil && inst.AutoScalingGroup != "" {
+			out.Unmapped = append(out.Unmapped, Unmapped{r.InstanceID, r.SuggestedType,
+				fmt.Sprintf("instance belongs to Auto Scaling group %s; pass --template-dir to find its launch template", inst.AutoScalingGroup)})
+			continue
+		}
+
+		target, reason := resolveCfnTarget(tmpl, logicalID)
+		if reason != "" {
+			out.Unmapped = append(out.Unmapped, Unmapped{r.InstanceID, r.SuggestedType, reason})
+			continue
+		}
+
+		key := stack + "/" + target.parameter + "/" + target.logicalID + "/" + target.propertyPath
+		p, ok := targets[key]
+		if !ok {
+			p = &pending{stack: stack, target: target}
+			targets[key] = p
+			targetOrder = append(targetOrder, key)
+		}
+		p.recs = append(p.recs, r)
+	}
+
+	for _, key := range targetOrder {
+		p := targets[key]
+
+		newType := p.recs[0].SuggestedType
+		var ids []string
+		conflict := false
+		for _, r := range p.recs {
+			ids = append(ids, r.InstanceID)
+			if r.SuggestedType != newType || r.InstanceType != p.recs[0].InstanceType {
+				conflict = true
+			}
+		}
+		if conflict {
+			for _, r := range p.recs {
+				out.Unmapped = append(out.Unmapped, Unmapped{r.InstanceID, r.SuggestedType,
+					fmt.Sprintf("instances sharing %s in %s have conflicting types or suggestions", p.target.describe(), p.stack)})
+			}
+			continue
+		}
+
+		s, ok := stacks[p.stack]
+		if !ok {
+			s = &StackChangeSet{StackName: p.stack}
+			stacks[p.stack] = s
+			stackOrder = append(stackOrder, p.stack)
+		}
+
+		if p.target.parameter != "" {
+			s.Parameters = append(s.Parameters, ParameterChange{
+				ParameterKey:  p.target.parameter,
+				CurrentValue:  p.recs[0].InstanceType,
+				ProposedValue: newType,
+				InstanceIDs:   ids,
+			})
+		} else {
+			s.ResourceChanges = append(s.ResourceChanges, ResourceChange{
+				LogicalID:     p.target.logicalID,
+				ResourceType:  p.target.resourceType,
+				PropertyPath:  p.target.propertyPath,
+				CurrentValue:  p.recs[0].InstanceType,
+				ProposedValue: newType,
+				InstanceIDs:   ids,
+			})
+		}
+	}
+
+	sort.Strings(stackOrder)
+	for _, name := range stackOrder {
+		out.Stacks = append(out.Stacks, *stacks[name])
+	}
+
+	return out, nil
+}
+
+func (t cfnTarget) describe() string {
+	if t.parameter != "" {
+		return "parameter " + t.parameter
+	}
+	return t.logicalID
+}
+
+// loadCfnTemplate reads a stack's JSON template; a missing file is not an error
+func loadCfnTemplate(dir, stack string) (*cfnTemplate, error) {
+	for _, name := range []string{stack + ".template.json", stack + ".json"} {
+		file, err := os.ReadFile(filepath.Join(dir, name))
+		if os.IsNotExist(err) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read template for %s: %w", stack, err)
+		}
+
+		var t cfnTemplate
+		if err := json.Unmarshal(file, &t); err != nil {
+			return nil, fmt.Errorf("failed to parse template %s: %w", name, err)
+		}
+		return &t, nil
+	}
+	return nil, nil
+}
+
+// resolveCfnTarget finds where the template declares the instance type for a
+// logical resource. Without a template, an AWS::EC2::Instance is assumed;
+// callers rule out Auto Scaling group members first.
+func resolveCfnTarget(t *cfnTemplate, logicalID string) (cfnTarget, string) {
+	if t == nil {
+		return cfnTarget{
+			logicalID:    logicalID,
+			resourceType: "AWS::EC2::Instance",
+			propertyPath: "Properties.InstanceType",
+		}, ""
+	}
+
+	res, ok := t.Resources[logicalID]
+	if !ok {
+		return cfnTarget{}, fmt.Sprintf("resource %s not found in template", logicalID)
+	}
+
+	switch res.Type {
+	case "AWS::EC2::Instance":
+		return typeFromValue(t, logicalID, res.Type, "Properties.InstanceType", res.Properties["InstanceType"])
+
+	case "AWS::EC2::LaunchTemplate":
+		var data map[string]json.RawMessage
+		if err := json.Unmarshal(res.Properties["LaunchTemplateData"], &data); err != nil {
+			return cfnTarget{}, fmt.Sprintf("launch template %s has no LaunchTemplateData", logicalID)
+		}
+		return typeFromValue(t, logicalID, res.Type, "Properties.LaunchTemplateData.InstanceType", data["InstanceType"])
+
+	case "AWS::AutoScaling::AutoScalingGroup":
+		var spec struct {
+			LaunchTemplateID json.RawMessage `json:"LaunchTemplateId"`
+		}
+		if err := json.Unmarshal(res.Properties["LaunchTemplate"], &spec); err != nil {
+			return cfnTarget{}, fmt.Sprintf("Auto Scaling group %s does not use a launch template", logicalID)
+		}
+		ltID, ok := refName(spec.LaunchTemplateID)
+		if !ok {
+			return cfnTarget{}, fmt.Sprintf("Auto Scaling group %s references a launch template outside the stack", logicalID)
+		}
+		return resolveCfnTarget(t, ltID)
+
+	default:
+		return cfnTarget{}, fmt.Sprintf("resource %s has unsupported type %s", logicalID, res.Type)
+	}
+}
+
+// typeFromValue classifies an InstanceType value as a literal or a parameter Ref
+func typeFromValue(t *cfnTemplate, logicalID, resourceType, path string, v json.RawMessage) (cfnTarget, string) {
+	if len(v) == 0 {
+		return cfnTarget{}, fmt.Sprintf("%s does not set InstanceType", logicalID)
+	}
+
+	var literal string
+	if err := json.Unmarshal(v, &literal); err == nil {
+		return cfnTarget{logicalID: logicalID, resourceType: resourceType, propertyPath: path}, ""
+	}
+
+	if param, ok := refName(v); ok {
+		if _, isParam := t.Parameters[param]; isParam {
+			return cfnTarget{parameter: param}, ""
+		}
+	}
+
+	return cfnTarget{}, fmt.Sprintf("%s sets InstanceType from an intrinsic function; edit manually", logicalID)
+}
+
+// refName extracts X from {"Ref": "X"}
+func refName(v json.RawMessage) (string, bool) {
+	var ref struct {
+		Ref string `json:"Ref"`
+	}
+	if err := json.Unmarshal(v, &ref); err != nil || ref.Ref == "" {
+		return "", false
+	}
+	return ref.Ref, true
+}
+
+// YAML renders the export as YAML for review
+func (e CloudFormationExport) YAML() string {
+	var sb strings.Builder
+	q := strconv.Quote
+
+	writeIDs := func(indent string, ids []string) {
+		sb.WriteString(indent + "instance_ids:\n")
+		for _, id := range ids {
+			sb.WriteString(indent + "  - " + q(id) + "\n")
+		}
+	}
+
+	if len(e.Stacks) == 0 {
+		sb.WriteString("stacks: []\n")
+	} else {
+		sb.WriteString("stacks:\n")
+	}
+	for _, s := range e.Stacks {
+		sb.WriteString("  - stack_name: " + q(s.StackName) + "\n")
+		if len(s.Parameters) > 0 {
+			sb.WriteString("    parameters:\n")
+			for _, p := range s.Parameters {
+				sb.WriteString("      - parameter_key: " + q(p.ParameterKey) + "\n")
+				sb.WriteString("        current_value: " + q(p.CurrentValue) + "\n")
+				sb.WriteString("        proposed_value: " + q(p.ProposedValue) + "\n")
+				writeIDs("        ", p.InstanceIDs)
+			}
+		}
+		if len(s.ResourceChanges) > 0 {
+			sb.WriteString("    resource_changes:\n")
+			for _, c := range s.ResourceChanges {
+				sb.WriteString("      - logical_id: " + q(c.LogicalID) + "\n")
+				sb.WriteString("        resource_type: " + q(c.ResourceType) + "\n")
+				sb.WriteString("        property_path: " + q(c.PropertyPath) + "\n")
+				sb.WriteString("        current_value: " + q(c.CurrentValue) + "\n")
+				sb.WriteString("        proposed_value: " + q(c.ProposedValue) + "\n")
+				writeIDs("        ", c.InstanceIDs)
+			}
+		}
+	}
+
+	if len(e.Unmapped) > 0 {
+		sb.WriteString("unmapped:\n")
+		for _, u := range e.Unmapped {
+			sb.WriteString("  - instance_id: " + q(u.InstanceID) + "\n")
+			sb.WriteString("    suggested_type: " + q(u.SuggestedType) + "\n")
+			sb.WriteString("    reason: " + q(u.Reason) + "\n")
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,156 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// cfnInstance returns an instance carrying CloudFormation's stack tags
+func cfnInstance(id, stack, logicalID, asg string) model.EC2Instance {
+	return model.EC2Instance{ID: id, AutoScalingGroup: asg, Tags: map[string]string{stackNameTag: stack, logicalIDTag: logicalID}}
+}
+
+func TestPlanCloudFormationTemplate(t *testing.T) {
+	dir := t.TempDir()
+	writeFiles(t, dir, map[string]string{"app.template.json": `{
+		"Parameters": {"DbType": {"Type": "String", "Default": "r5.large"}},
+		"Resources": {
+			"Db": {"Type": "AWS::EC2::Instance", "Properties": {"InstanceType": {"Ref": "DbType"}}},
+			"WebAsg": {"Type": "AWS::AutoScaling::AutoScalingGroup", "Properties": {"LaunchTemplate": {"LaunchTemplateId": {"Ref": "WebLt"}, "Version": "1"}}},
+			"WebLt": {"Type": "AWS::EC2::LaunchTemplate", "Properties": {"LaunchTemplateData": {"InstanceType": "m5.xlarge"}}},
+			"Worker": {"Type": "AWS::EC2::Instance", "Properties": {"InstanceType": {"Fn::FindInMap": ["Sizes", "prod", "worker"]}}},
+			"SharedAsg": {"Type": "AWS::AutoScaling::AutoScalingGroup", "Properties": {"LaunchTemplate": {"LaunchTemplateId": "lt-0123", "Version": "1"}}}
+		}
+	}`})
+
+	instances := map[string]model.EC2Instance{
+		"i-db":     cfnInstance("i-db", "app", "Db", ""),
+		"i-web1":   cfnInstance("i-web1", "app", "WebAsg", "app-web"),
+		"i-web2":   cfnInstance("i-web2", "app", "WebAsg", "app-web"),
+		"i-worker": cfnInstance("i-worker", "app", "Worker", ""),
+		"i-shared": cfnInstance("i-shared", "app", "SharedAsg", "shared"),
+	}
+	recs := []model.Recommendation{
+		{InstanceID: "i-db", InstanceType: "r5.large", SuggestedType: "r6i.large"},
+		{InstanceID: "i-web1", InstanceType: "m5.xlarge", SuggestedType: "m5.large"},
+		{InstanceID: "i-web2", InstanceType: "m5.xlarge", SuggestedType: "m5.large"},
+		{InstanceID: "i-worker", InstanceType: "c5.large", SuggestedType: "c6i.large"},
+		{InstanceID: "i-shared", InstanceType: "m5.large", SuggestedType: "m6i.large"},
+	}
+
+	out, err := PlanCloudFormation(recs, instances, dir)
+	if err != nil {
+		t.Fatalf("PlanCloudFormation: %v", err)
+	}
+	if len(out.Stacks) != 1 || out.Stacks[0].StackName != "app" {
+		t.Fatalf("stacks = %+v, want app only", out.Stacks)
+	}
+	s := out.Stacks[0]
+
+	wantParam := ParameterChange{ParameterKey: "DbType", CurrentValue: "r5.large", ProposedValue: "r6i.large", InstanceIDs: []string{"i-db"}}
+	if len(s.Parameters) != 1 || !sameParameter(s.Parameters[0], wantParam) {
+		t.Errorf("parameters = %+v, want %+v", s.Parameters, wantParam)
+	}
+
+	// Both group members follow the ASG's Ref to one launch template edit
+	wantRes := ResourceChange{LogicalID: "WebLt", ResourceType: "AWS::EC2::LaunchTemplate", PropertyPath: "Properties.LaunchTemplateData.InstanceType",
+		CurrentValue: "m5.xlarge", ProposedValue: "m5.large", InstanceIDs: []string{"i-web1", "i-web2"}}
+	if len(s.ResourceChanges) != 1 || !sameResource(s.ResourceChanges[0], wantRes) {
+		t.Errorf("resource changes = %+v, want %+v", s.ResourceChanges, wantRes)
+	}
+
+	reasons := unmappedReasons(out.Unmapped)
+	for id, want := range map[string]string{"i-worker": "intrinsic function", "i-shared": "outside the stack"} {
+		if !strings.Contains(reasons[id], want) {
+			t.Errorf("%s unmapped reason = %q, want it to mention %q", id, reasons[id], want)
+		}
+	}
+	if len(out.Unmapped) != 2 {
+		t.Errorf("unmapped = %+v, want i-worker and i-shared", out.Unmapped)
+	}
+}
+
+func TestPlanCloudFormationWithoutTemplate(t *testing.T) {
+	instances := map[string]model.EC2Instance{
+		"i-app": cfnInstance("i-app", "app", "AppServer", ""),
+		"i-web": cfnInstance("i-web", "app", "WebAsg", "app-web"),
+		"i-raw": {ID: "i-raw"},
+	}
+	recs := []model.Recommendation{
+		{InstanceID: "i-app", InstanceType: "m5.xlarge", SuggestedType: "m5.large"},
+		{InstanceID: "i-web", InstanceType: "m5.xlarge", SuggestedType: "m5.large"},
+		{InstanceID: "i-raw", InstanceType: "m5.xlarge", SuggestedType: "m5.large"},
+	}
+
+	out, err := PlanCloudFormation(recs, instances, "")
+	if err != nil {
+		t.Fatalf("PlanCloudFormation: %v", err)
+	}
+
+	want := ResourceChange{LogicalID: "AppServer", ResourceType: "AWS::EC2::Instance", PropertyPath: "Properties.InstanceType",
+		CurrentValue: "m5.xlarge", ProposedValue: "m5.large", InstanceIDs: []string{"i-app"}}
+	if len(out.Stacks) != 1 || len(out.Stacks[0].ResourceChanges) != 1 || !sameResource(out.Stacks[0].ResourceChanges[0], want) {
+		t.Errorf("stacks = %+v, want only %+v", out.Stacks, want)
+	}
+
+	// An ASG member is flagged rather than guessed to be an instance resource
+	reasons := unmappedReasons(out.Unmapped)
+	if !strings.Contains(reasons["i-web"], "Auto Scaling group app-web") {
+		t.Errorf("i-web unmapped reason = %q, want the Auto Scaling group named", reasons["i-web"])
+	}
+	if !strings.Contains(reasons["i-raw"], "no CloudFormation stack tags") {
+		t.Errorf("i-raw unmapped reason = %q, want missing stack tags", reasons["i-raw"])
+	}
+}
+
+func TestCloudFormationYAML(t *testing.T) {
+	e := CloudFormationExport{
+		Stacks: []StackChangeSet{{
+			StackName:  "app",
+			Parameters: []ParameterChange{{ParameterKey: "DbType", CurrentValue: "r5.large", ProposedValue: "r6i.large", InstanceIDs: []string{"i-db"}}},
+		}},
+		Unmapped: []Unmapped{{InstanceID: "i-x", SuggestedType: "m5.large", Reason: `sets "InstanceType": edit: manually`}},
+	}
+	want := `stacks:
+  - stack_name: "app"
+    parameters:
+      - parameter_key: "DbType"
+        current_value: "r5.large"
+        proposed_value: "r6i.large"
+        instance_ids:
+          - "i-db"
+unmapped:
+  - instance_id: "i-x"
+    suggested_type: "m5.large"
+    reason: "sets \"InstanceType\": edit: manually"
+`
+	if got := e.YAML(); got != want {
+		t.Errorf("YAML =\n%s\nwant\n%s", got, want)
+	}
+
+	if got := (CloudFormationExport{}).YAML(); got != "stacks: []\n" {
+		t.Errorf("empty YAML = %q, want an empty stack list", got)
+	}
+}
+
+// unmappedReasons indexes unmapped reasons by instance ID
+func unmappedReasons(us []Unmapped) map[string]string {
+	out := map[string]string{}
+	for _, u := range us {
+		out[u.InstanceID] = u.Reason
+	}
+	return out
+}
+
+func sameParameter(a, b ParameterChange) bool {
+	return a.ParameterKey == b.ParameterKey && a.CurrentValue == b.CurrentValue && a.ProposedValue == b.ProposedValue &&
+		strings.Join(a.InstanceIDs, ",") == strings.Join(b.InstanceIDs, ",")
+}
+
+func sameResource(a, b ResourceChange) bool {
+	return a.LogicalID == b.LogicalID && a.ResourceType == b.ResourceType && a.PropertyPath == b.PropertyPath &&
+		a.CurrentValue == b.CurrentValue && a.ProposedValue == b.ProposedValue &&
+		strings.Join(a.InstanceIDs, ",") == strings.Join(b.InstanceIDs, ",")
+}
@@ -0,0 +1,68 @@
+package export
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/catalog"
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// LoadReport reads recommendations saved with `recommend --output json` (a
+// bare array) or `--output json-report` (a {"summary", "recommendations"}
+// document).
+func LoadReport(path string) ([]model.Recommendation, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read report: %w", err)
+	}
+
+	var recs []model.Recommendation
+	if trimmed := bytes.TrimSpace(file); len(trimmed) > 0 && trimmed[0] == '[' {
+		if err := json.Unmarshal(trimmed, &recs); err != nil {
+			return nil, fmt.Errorf("failed to parse report: %w", err)
+		}
+		return recs, nil
+	}
+
+	var doc struct {
+		Recommendations []model.Recommendation `json:"recommendations"`
+	}
+	if err := json.Unmarshal(file, &doc); err != nil {
+		return nil, fmt.Errorf("failed to parse report: %w", err)
+	}
+	return doc.Recommendations, nil
+}
+
+// typeChanges returns recommendations that propose a different instance
+// type. Changes across architectures (e.g. Graviton migrations) are returned
+// unmapped: the instance's AMI would not boot on the new type, so they cannot
+// be exported as an instance type edit.
+func typeChanges(recs []model.Recommendation) ([]model.Recommendation, []Unmapped) {
+	cat := catalog.Default()
+
+	var out []model.Recommendation
+	var unmapped []Unmapped
+	for _, r := range recs {
+		if r.SuggestedType == "" || r.SuggestedType == r.InstanceType {
+			continue
+		}
+		from, fromOK := cat.FamilyOf(r.InstanceType)
+		to, toOK := cat.FamilyOf(r.SuggestedType)
+		if fromOK && toOK && from.Architecture != to.Architecture {
+			unmapped = append(unmapped, Unmapped{r.InstanceID, r.SuggestedType, "AMI must be rebuilt for " + to.Architecture})
+			continue
+		}
+		out = append(out, r)
+	}
+	return out, unmapped
+}
+
+// Unmapped describes a recommendation that could not be traced back to code.
+type Unmapped struct {
+	InstanceID    string `json:"instance_id"`
+	SuggestedType string `json:"suggested_type"`
+	Reason        string `json:"reason"`
+}
@@ -0,0 +1,26 @@
+package export
+
+import (
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func TestTypeChanges(t *testing.T) {
+	recs := []model.Recommendation{
+		{InstanceID: "i-keep", InstanceType: "m5.large"},
+		{InstanceID: "i-same", InstanceType: "m5.large", SuggestedType: "m5.large"},
+		{InstanceID: "i-down", InstanceType: "m5.xlarge", SuggestedType: "m5.large"},
+		{InstanceID: "i-graviton", InstanceType: "m5.large", SuggestedType: "m7g.large"},
+		{InstanceID: "i-unknown", InstanceType: "x9.large", SuggestedType: "m5.large"},
+	}
+
+	changes, unmapped := typeChanges(recs)
+	if len(changes) != 2 || changes[0].InstanceID != "i-down" || changes[1].InstanceID != "i-unknown" {
+		t.Errorf("changes = %+v, want i-down and i-unknown", changes)
+	}
+	want := Unmapped{InstanceID: "i-graviton", SuggestedType: "m7g.large", Reason: "AMI must be rebuilt for arm64"}
+	if len(unmapped) != 1 || unmapped[0] != want {
+		t.Errorf("unmapped = %+v, want %+v", unmapped, want)
+	}
+}
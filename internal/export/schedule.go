@@ -0,0 +1,273 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// SchedulerRolePlaceholder stands in for the EventBridge Scheduler execution
+// role when none is given
+const SchedulerRolePlaceholder = "arn:aws:iam::ACCOUNT_ID:role/SCHEDULER_ROLE"
+
+// schedulePrefix names everything generated for schedules
+const schedulePrefix = "cloud-optimiser"
+
+// EventBridgeSchedule is one EventBridge Scheduler CreateSchedule request,
+// usable with `aws scheduler create-schedule --cli-input-json`.
+type EventBridgeSchedule struct {
+	Name                       string             `json:"Name"`
+	Description                string             `json:"Description"`
+	ScheduleExpression         string             `json:"ScheduleExpression"`
+	ScheduleExpressionTimezone string             `json:"ScheduleExpressionTimezone"`
+	FlexibleTimeWindow         FlexibleTimeWindow `json:"FlexibleTimeWindow"`
+	Target                     SchedulerTarget    `json:"Target"`
+	State                      string             `json:"State"`
+}
+
+// FlexibleTimeWindow is the Scheduler invocation window; schedules run on time.
+type FlexibleTimeWindow struct {
+	Mode string `json:"Mode"`
+}
+
+// SchedulerTarget is a universal target calling one AWS API action.
+type SchedulerTarget struct {
+	Arn     string `json:"Arn"`
+	RoleArn string `json:"RoleArn"`
+	Input   string `json:"Input"`
+}
+
+// InstanceSchedulerConfig holds the periods and schedules to add to Instance
+// Scheduler on AWS, and the Schedule tags that attach resources to them.
+type InstanceSchedulerConfig struct {
+	Periods   []SchedulerPeriod   `json:"periods"`
+	Schedules []SchedulerSchedule `json:"schedules"`
+	Resources []ScheduledResource `json:"resources"`
+}
+
+// SchedulerPeriod is an Instance Scheduler running period.
+type SchedulerPeriod struct {
+	Name      string `json:"name"`
+	BeginTime string `json:"begintime"`
+	EndTime   string `json:"endtime"`
+	Weekdays  string `json:"weekdays"`
+}
+
+// SchedulerSchedule is an Instance Scheduler schedule made of periods.
+type SchedulerSchedule struct {
+	Name     string   `json:"name"`
+	Periods  []string `json:"periods"`
+	Timezone string   `json:"timezone"`
+}
+
+// ScheduledResource is an instance or Auto Scaling group to tag with its schedule.
+type ScheduledResource struct {
+	Type string            `json:"type"` // instance | autoscaling-group
+	ID   string            `json:"id"`
+	Tags map[string]string `json:"tags"`
+}
+
+// scheduleGroup is the set of resources sharing one window
+type scheduleGroup struct {
+	name      string
+	advice    model.ScheduleAdvice
+	instances []string
+	asg       string
+}
+
+// groupSchedules collects suitable advice into one group per window. Auto
+// Scaling group members are scheduled through their group instead: its window
+// covers every member's, and a group with any member that cannot be stopped
+// is left out.
+func groupSchedules(advice []model.ScheduleAdvice) []scheduleGroup {
+	byName := map[string]*scheduleGroup{}
+	var names []string
+	busy := map[string]bool{}
+	for _, a := range advice {
+		if !a.Suitable {
+			if a.AutoScalingGroup != "" {
+				busy[a.AutoScalingGroup] = true
+			}
+			continue
+		}
+		name := windowName(a)
+		if a.AutoScalingGroup != "" {
+			name = schedulePrefix + "-" + a.AutoScalingGroup
+		}
+		g, ok := byName[name]
+		if !ok {
+			g = &scheduleGroup{name: name, advice: a, asg: a.AutoScalingGroup}
+			byName[name] = g
+			names = append(names, name)
+		} else {
+			g.advice = widenWindow(g.advice, a)
+		}
+		g.instances = append(g.instances, a.InstanceID)
+	}
+	sort.Strings(names)
+
+	out := make([]scheduleGroup, 0, len(names))
+	for _, name := range names {
+		g := byName[name]
+		if busy[g.asg] {
+			continue
+		}
+		sort.Strings(g.instances)
+		out = append(out, *g)
+	}
+	return out
+}
+
+// widenWindow returns a window covering both members': every day either
+// runs, from the earlier start to the later stop
+func widenWindow(a, b model.ScheduleAdvice) model.ScheduleAdvice {
+	days := map[string]bool{}
+	for _, d := range append(append([]string(nil), a.Days...), b.Days...) {
+		days[d] = true
+	}
+	a.Days = nil
+	for _, d := range []string{"MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"} {
+		if days[d] {
+			a.Days = append(a.Days, d)
+		}
+	}
+	a.StartHour = min(a.StartHour, b.StartHour)
+	a.StopHour = max(a.StopHour, b.StopHour)
+	return a
+}
+
+// EventBridgeSchedules builds a start and a stop schedule per window. Plain
+// instances call StartInstances/StopInstances; Auto Scaling groups are
+// scaled to zero at the stop time and back to their recorded minimum and
+// desired capacity at the start, so groups must hold every scheduled group.
+func EventBridgeSchedules(advice []model.ScheduleAdvice, groups []model.AutoScalingGroup, roleArn string) ([]EventBridgeSchedule, error) {
+	if roleArn == "" {
+		roleArn = SchedulerRolePlaceholder
+	}
+	capacity := map[string]model.AutoScalingGroup{}
+	for _, g := range groups {
+		capacity[g.Name] = g
+	}
+
+	var out []EventBridgeSchedule
+	for _, g := range groupSchedules(advice) {
+		a := g.advice
+		days := strings.Join(a.Days, ",")
+
+		var startArn, stopArn string
+		var startInput, stopInput any
+		if g.asg != "" {
+			c, ok := capacity[g.asg]
+			if !ok {
+				return nil, fmt.Errorf("no recorded capacity for Auto Scaling group %s", g.asg)
+			}
+			startArn = "arn:aws:scheduler:::aws-sdk:autoscaling:updateAutoScalingGroup"
+			stopArn = startArn
+			startInput = map[string]any{"AutoScalingGroupName": g.asg, "MinSize": c.MinSize, "DesiredCapacity": c.DesiredCapacity}
+			stopInput = map[string]any{"AutoScalingGroupName": g.asg, "MinSize": 0, "DesiredCapacity": 0}
+		} else {
+			startArn = "arn:aws:scheduler:::aws-sdk:ec2:startInstances"
+			stopArn = "arn:aws:scheduler:::aws-sdk:ec2:stopInstances"
+			startInput = map[string]any{"InstanceIds": g.instances}
+			stopInput = startInput
+		}
+
+		for _, s := range []struct {
+			action string
+			label  string
+			cron   string
+			arn    string
+			input  any
+		}{
+			{"start", "Start", fmt.Sprintf("cron(0 %d ? * %s *)", a.StartHour, days), startArn, startInput},
+			{"stop", "Stop", stopCron(a.StopHour, days), stopArn, stopInput},
+		} {
+			input, err := json.Marshal(s.input)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode %s input: %w", s.action, err)
+			}
+			out = append(out, EventBridgeSchedule{
+				Name:                       g.name + "-" + s.action,
+				Description:                fmt.Sprintf("%s %s (off-hours schedule)", s.label, strings.Join(targets(g), ", ")),
+				ScheduleExpression:         s.cron,
+				ScheduleExpressionTimezone: a.Timezone,
+				FlexibleTimeWindow:         FlexibleTimeWindow{Mode: "OFF"},
+				Target:                     SchedulerTarget{Arn: s.arn, RoleArn: roleArn, Input: string(input)},
+				State:                      "ENABLED",
+			})
+		}
+	}
+	return out, nil
+}
+
+// InstanceScheduler builds one period and schedule per window and the
+// Schedule tag for each instance or Auto Scaling group.
+func InstanceScheduler(advice []model.ScheduleAdvice) InstanceSchedulerConfig {
+	cfg := InstanceSchedulerConfig{
+		Periods:   []SchedulerPeriod{},
+		Schedules: []SchedulerSchedule{},
+		Resources: []ScheduledResource{},
+	}
+	for _, g := range groupSchedules(advice) {
+		a := g.advice
+		end := fmt.Sprintf("%02d:00", a.StopHour)
+		if a.StopHour >= 24 {
+			end = "23:59"
+		}
+		cfg.Periods = append(cfg.Periods, SchedulerPeriod{
+			Name:      g.name,
+			BeginTime: fmt.Sprintf("%02d:00", a.StartHour),
+			EndTime:   end,
+			Weekdays:  strings.ToLower(strings.Join(a.Days, ",")),
+		})
+		cfg.Schedules = append(cfg.Schedules, SchedulerSchedule{Name: g.name, Periods: []string{g.name}, Timezone: a.Timezone})
+
+		tags := map[string]string{"Schedule": g.name}
+		if g.asg != "" {
+			cfg.Resources = append(cfg.Resources, ScheduledResource{Type: "autoscaling-group", ID: g.asg, Tags: tags})
+			continue
+		}
+		for _, id := range g.instances {
+			cfg.Resources = append(cfg.Resources, ScheduledResource{Type: "instance", ID: id, Tags: tags})
+		}
+	}
+	return cfg
+}
+
+// windowName names a schedule after its window, e.g. cloud-optimiser-mon-fri-0700-1900
+func windowName(a model.ScheduleAdvice) string {
+	days := strings.ToLower(strings.Join(a.Days, ""))
+	if len(a.Days) > 1 {
+		days = strings.ToLower(a.Days[0] + "-" + a.Days[len(a.Days)-1])
+		if len(a.Days) != dayDistance(a.Days[0], a.Days[len(a.Days)-1])+1 {
+			days = strings.ToLower(strings.Join(a.Days, "-"))
+		}
+	}
+	return fmt.Sprintf("%s-%s-%02d00-%02d00", schedulePrefix, days, a.StartHour, a.StopHour)
+}
+
+// dayDistance counts days from one day name to another, Monday first
+func dayDistance(from, to string) int {
+	order := "MONTUEWEDTHUFRISATSUN"
+	return (strings.Index(order, to) - strings.Index(order, from)) / 3
+}
+
+// stopCron stops at the top of the hour, or just before midnight for a
+// window running to the end of the day
+func stopCron(hour int, days string) string {
+	if hour >= 24 {
+		return fmt.Sprintf("cron(59 23 ? * %s *)", days)
+	}
+	return fmt.Sprintf("cron(0 %d ? * %s *)", hour, days)
+}
+
+// targets describes what a schedule acts on
+func targets(g scheduleGroup) []string {
+	if g.asg != "" {
+		return []string{"Auto Scaling group " + g.asg}
+	}
+	return g.instances
+}
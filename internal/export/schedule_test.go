@@ -0,0 +1,68 @@
+package export
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// member returns suitable schedule advice for an Auto Scaling group member
+func member(id, asg string, days []string, start, stop int) model.ScheduleAdvice {
+	return model.ScheduleAdvice{InstanceID: id, AutoScalingGroup: asg, Suitable: true, Timezone: "UTC", Days: days, StartHour: start, StopHour: stop}
+}
+
+func TestGroupSchedulesWidensGroupWindow(t *testing.T) {
+	weekdays := []string{"MON", "TUE", "WED", "THU", "FRI"}
+	advice := []model.ScheduleAdvice{
+		member("i-b", "web", []string{"MON", "TUE", "WED"}, 8, 18),
+		member("i-a", "web", []string{"WED", "SAT"}, 6, 17),
+		member("i-c", "", weekdays, 7, 19),
+		member("i-d", "", weekdays, 7, 19),
+		member("i-e", "api", weekdays, 7, 19),
+		{InstanceID: "i-f", AutoScalingGroup: "api", Reason: "busy overnight"},
+	}
+
+	groups := groupSchedules(advice)
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups, want 2 (the api group has a busy member): %+v", len(groups), groups)
+	}
+
+	plain, web := groups[0], groups[1]
+	if plain.asg != "" || strings.Join(plain.instances, ",") != "i-c,i-d" {
+		t.Errorf("plain group = %+v, want i-c and i-d", plain)
+	}
+	if web.asg != "web" || strings.Join(web.instances, ",") != "i-a,i-b" {
+		t.Errorf("web group = %+v, want i-a and i-b", web)
+	}
+	if got := strings.Join(web.advice.Days, ","); got != "MON,TUE,WED,SAT" || web.advice.StartHour != 6 || web.advice.StopHour != 18 {
+		t.Errorf("web window = %s %02d-%02d, want MON,TUE,WED,SAT 06-18", got, web.advice.StartHour, web.advice.StopHour)
+	}
+}
+
+func TestEventBridgeSchedulesRestoreGroupCapacity(t *testing.T) {
+	advice := []model.ScheduleAdvice{member("i-a", "web", []string{"MON"}, 7, 19)}
+	groups := []model.AutoScalingGroup{{Name: "web", MinSize: 2, MaxSize: 6, DesiredCapacity: 3}}
+
+	schedules, err := EventBridgeSchedules(advice, groups, "")
+	if err != nil {
+		t.Fatalf("EventBridgeSchedules: %v", err)
+	}
+	inputs := map[string]string{}
+	for _, s := range schedules {
+		inputs[s.Name] = s.Target.Input
+	}
+	want := map[string]string{
+		"cloud-optimiser-web-start": `{"AutoScalingGroupName":"web","DesiredCapacity":3,"MinSize":2}`,
+		"cloud-optimiser-web-stop":  `{"AutoScalingGroupName":"web","DesiredCapacity":0,"MinSize":0}`,
+	}
+	for name, input := range want {
+		if inputs[name] != input {
+			t.Errorf("%s input = %s, want %s", name, inputs[name], input)
+		}
+	}
+
+	if _, err := EventBridgeSchedules(advice, nil, ""); err == nil || !strings.Contains(err.Error(), "web") {
+		t.Errorf("expected an error for a group without recorded capacity, got %v", err)
+	}
+}
@@ -0,0 +1,423 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// launchTemplateTag is set by EC2 on instances launched from a launch template
+const launchTemplateTag = "aws:ec2launchtemplate:id"
+
+var (
+	resourceHeaderRe = regexp.MustCompile(`^\s*resource\s+"([^"]+)"\s+"([^"]+)"\s*\{`)
+	instanceTypeRe   = regexp.MustCompile(`^(\s*instance_type\s*=\s*")([^"]*)(".*)$`)
+	indexKeyRe       = regexp.MustCompile(`\[[^\]]*\]`)
+)
+
+// TerraformResource is an aws_instance or aws_launch_template found in state.
+type TerraformResource struct {
+	Address      string
+	Type         string
+	Name         string
+	ID           string
+	InstanceType string
+	Tags         map[string]string
+}
+
+// TerraformEdit is a single instance_type change in a .tf file.
+type TerraformEdit struct {
+	InstanceIDs []string `json:"instance_ids"`
+	Address     string   `json:"address"`
+	File        string   `json:"file"`
+	Line        int      `json:"line"`
+	OldType     string   `json:"old_type"`
+	NewType     string   `json:"new_type"`
+	MatchedBy   string   `json:"matched_by"`
+}
+
+// TerraformPlan is the result of mapping recommendations to Terraform code.
+type TerraformPlan struct {
+	Edits    []TerraformEdit `json:"edits"`
+	Unmapped []Unmapped      `json:"unmapped"`
+}
+
+// stateAttributes holds the attributes we read from state, in either format
+type stateAttributes struct {
+	ID           string            `json:"id"`
+	InstanceType string            `json:"instance_type"`
+	Tags         map[string]string `json:"tags"`
+}
+
+// tfstate v4 file layout
+type rawState struct {
+	Resources []struct {
+		Module    string `json:"module"`
+		Mode      string `json:"mode"`
+		Type      string `json:"type"`
+		Name      string `json:"name"`
+		Instances []struct {
+			IndexKey   any             `json:"index_key"`
+			Attributes stateAttributes `json:"attributes"`
+		} `json:"instances"`
+	} `json:"resources"`
+	Values *struct {
+		RootModule showModule `json:"root_module"`
+	} `json:"values"`
+}
+
+// `terraform show -json` module layout
+type showModule struct {
+	Resources []struct {
+		Address string          `json:"address"`
+		Mode    string          `json:"mode"`
+		Type    string          `json:"type"`
+		Name    string          `json:"name"`
+		Values  stateAttributes `json:"values"`
+	} `json:"resources"`
+	ChildModules []showModule `json:"child_modules"`
+}
+
+// LoadTerraformState reads aws_instance and aws_launch_template resources from
+// a terraform.tfstate file or the output of `terraform show -json`.
+func LoadTerraformState(path string) ([]TerraformResource, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Terraform state: %w", err)
+	}
+
+	var raw rawState
+	if err := json.Unmarshal(file, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse Terraform state: %w", err)
+	}
+
+	var out []TerraformResource
+
+	for _, res := range raw.Resources {
+		if res.Mode != "managed" || !isMappedType(res.Type) {
+			continue
+		}
+		address := res.Type + "." + res.Name
+		if res.Module != "" {
+			address = res.Module + "." + address
+		}
+		for _, inst := range res.Instances {
+			addr := address
+			switch k := inst.IndexKey.(type) {
+			case string:
+				addr = fmt.Sprintf("%s[%q]", address, k)
+			case float64:
+				addr = fmt.Sprintf("%s[%d]", address, int(k))
+			}
+			out = append(out, newTerraformResource(addr, res.Type, res.Name, inst.Attributes))
+		}
+	}
+
+	if raw.Values != nil {
+		out = append(out, walkShowModule(raw.Values.RootModule)...)
+	}
+
+	return out, nil
+}
+
+// walkShowModule flattens resources from `terraform show -json` output
+func walkShowModule(m showModule) []TerraformResource {
+	var out []TerraformResource
+	for _, res := range m.Resources {
+		if res.Mode != "managed" || !isMappedType(res.Type) {
+			continue
+		}
+		out = append(out, newTerraformResource(res.Address, res.Type, res.Name, res.Values))
+	}
+	for _, child := range m.ChildModules {
+		out = append(out, walkShowModule(child)...)
+	}
+	return out
+}
+
+func newTerraformResource(address, typ, name string, attrs stateAttributes) TerraformResource {
+	return TerraformResource{
+		Address:      address,
+		Type:         typ,
+		Name:         name,
+		ID:           attrs.ID,
+		InstanceType: attrs.InstanceType,
+		Tags:         attrs.Tags,
+	}
+}
+
+func isMappedType(t string) bool {
+	return t == "aws_instance" || t == "aws_launch_template"
+}
+
+// PlanTerraform maps type-changing recommendations to instance_type
+// assignments in the .tf files under dir.
+//
+// Instances are matched to state by instance ID, then by launch template ID
+// tag, then by a unique Name tag. instances supplies live tags by instance ID.
+func PlanTerraform(
+	recs []model.Recommendation,
+	instances map[string]model.EC2Instance,
+	resources []TerraformResource,
+	dir string,
+) (TerraformPlan, error) {
+	var plan TerraformPlan
+
+	modules, err := newModuleResolver(dir)
+	if err != nil {
+		return plan, err
+	}
+
+	// Group recommendations by the resource they map to, since several
+	// instances can share one launch template
+	type target struct {
+		res       TerraformResource
+		matchedBy string
+		recs      []model.Recommendation
+	}
+	targets := map[string]*target{}
+	var order []string
+
+	changes, unmapped := typeChanges(recs)
+	plan.Unmapped = append(plan.Unmapped, unmapped...)
+	for _, r := range changes {
+		res, matchedBy, reason := matchResource(r, instances[r.InstanceID], resources)
+		if reason != "" {
+			plan.Unmapped = append(plan.Unmapped, Unmapped{r.InstanceID, r.SuggestedType, reason})
+			continue
+		}
+		// count/for_each instances, of the resource or its modules, share one block
+		key := indexKeyRe.ReplaceAllString(res.Address, "")
+		t, ok := targets[key]
+		if !ok {
+			t = &target{res: res, matchedBy: matchedBy}
+			targets[key] = t
+			order = append(order, key)
+		}
+		t.recs = append(t.recs, r)
+	}
+
+	for _, addr := range order {
+		t := targets[addr]
+
+		unmapAll := func(reason string) {
+			for _, r := range t.recs {
+				plan.Unmapped = append(plan.Unmapped, Unmapped{r.InstanceID, r.SuggestedType, reason})
+			}
+		}
+
+		newType := t.recs[0].SuggestedType
+		var ids []string
+		conflict := false
+		for _, r := range t.recs {
+			ids = append(ids, r.InstanceID)
+			if r.SuggestedType != newType {
+				conflict = true
+			}
+		}
+		if conflict {
+			unmapAll(fmt.Sprintf("instances sharing %s have conflicting suggestions", addr))
+			continue
+		}
+
+		module, err := modules.module(modulePath(t.res.Address))
+		if err != nil {
+			unmapAll(fmt.Sprintf("%s: %v", addr, err))
+			continue
+		}
+		matches := module.blocks[t.res.Type+"."+t.res.Name]
+		if len(matches) == 0 {
+			unmapAll(fmt.Sprintf("%s is in state but no matching resource block was found", addr))
+			continue
+		}
+		if len(matches) > 1 {
+			unmapAll(fmt.Sprintf("%s matches %d resource blocks; edit manually", addr, len(matches)))
+			continue
+		}
+
+		b := matches[0]
+		if b.typeLine == 0 {
+			unmapAll(fmt.Sprintf("%s sets instance_type from an expression, not a literal", addr))
+			continue
+		}
+		if b.instanceType != t.recs[0].InstanceType {
+			unmapAll(fmt.Sprintf("%s declares %s but the instance runs %s", addr, b.instanceType, t.recs[0].InstanceType))
+			continue
+		}
+
+		plan.Edits = append(plan.Edits, TerraformEdit{
+			InstanceIDs: ids,
+			Address:     addr,
+			File:        b.file,
+			Line:        b.typeLine,
+			OldType:     b.instanceType,
+			NewType:     newType,
+			MatchedBy:   t.matchedBy,
+		})
+	}
+
+	return plan, nil
+}
+
+// matchResource finds the state resource that manages an instance
+func matchResource(r model.Recommendation, inst model.EC2Instance, resources []TerraformResource) (TerraformResource, string, string) {
+	for _, res := range resources {
+		if res.Type == "aws_instance" && res.ID == r.InstanceID {
+			return res, "instance_id", ""
+		}
+	}
+
+	if ltID := inst.Tags[launchTemplateTag]; ltID != "" {
+		for _, res := range resources {
+			if res.Type == "aws_launch_template" && res.ID == ltID {
+				return res, "launch_template", ""
+			}
+		}
+	}
+
+	if name := inst.Tags["Name"]; name != "" {
+		var found []TerraformResource
+		for _, res := range resources {
+			if res.Type == "aws_instance" && res.Tags["Name"] == name {
+				found = append(found, res)
+			}
+		}
+		if len(found) == 1 {
+			return found[0], "name_tag", ""
+		}
+		if len(found) > 1 {
+			return TerraformResource{}, "", fmt.Sprintf("Name tag %q matches %d resources", name, len(found))
+		}
+	}
+
+	return TerraformResource{}, "", "no aws_instance or aws_launch_template in state manages this instance"
+}
+
+// tfBlock is a resource block located in a .tf file
+type tfBlock struct {
+	file         string
+	typeLine     int // 1-based; 0 when instance_type is not a string literal
+	instanceType string
+}
+
+// braceDelta counts block braces on a line, ignoring strings and comments
+func braceDelta(line string) int {
+	delta := 0
+	inString := false
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case inString && c == '\\':
+			i++
+		case c == '"':
+			inString = !inString
+		case inString:
+		case c == '#', c == '/' && i+1 < len(line) && line[i+1] == '/':
+			return delta
+		case c == '{':
+			delta++
+		case c == '}':
+			delta--
+		}
+	}
+	return delta
+}
+
+// readLines returns a file's lines with their line endings, so edits can
+// keep CRLF files as CRLF
+func readLines(path string) ([]string, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) == 0 {
+		return nil, nil
+	}
+	return strings.SplitAfter(string(b), "\n"), nil
+}
+
+// splitEOL separates a line from its line ending
+func splitEOL(line string) (string, string) {
+	body := strings.TrimRight(line, "\r\n")
+	return body, line[len(body):]
+}
+
+// Patch renders the edits as a zero-context unified diff relative to dir
+// (apply with `patch -p1` or `git apply --unidiff-zero`).
+func (p TerraformPlan) Patch(dir string) (string, error) {
+	byFile := map[string][]TerraformEdit{}
+	var files []string
+	for _, e := range p.Edits {
+		if _, ok := byFile[e.File]; !ok {
+			files = append(files, e.File)
+		}
+		byFile[e.File] = append(byFile[e.File], e)
+	}
+	sort.Strings(files)
+
+	var sb strings.Builder
+	for _, file := range files {
+		lines, err := readLines(file)
+		if err != nil {
+			return "", err
+		}
+
+		rel, err := filepath.Rel(dir, file)
+		if err != nil {
+			rel = file
+		}
+		rel = filepath.ToSlash(rel)
+
+		fmt.Fprintf(&sb, "--- a/%s\n+++ b/%s\n", rel, rel)
+
+		edits := byFile[file]
+		sort.Slice(edits, func(i, j int) bool { return edits[i].Line < edits[j].Line })
+		for _, e := range edits {
+			old, eol := splitEOL(lines[e.Line-1])
+			if eol == "" {
+				eol = "\n"
+			}
+			fmt.Fprintf(&sb, "@@ -%d +%d @@\n-%s%s+%s%s", e.Line, e.Line, old, eol, rewriteInstanceType(old, e.NewType), eol)
+		}
+	}
+
+	return sb.String(), nil
+}
+
+// Apply rewrites the .tf files in place.
+func (p TerraformPlan) Apply() error {
+	byFile := map[string][]TerraformEdit{}
+	for _, e := range p.Edits {
+		byFile[e.File] = append(byFile[e.File], e)
+	}
+
+	for file, edits := range byFile {
+		info, err := os.Stat(file)
+		if err != nil {
+			return err
+		}
+		lines, err := readLines(file)
+		if err != nil {
+			return err
+		}
+		for _, e := range edits {
+			old, eol := splitEOL(lines[e.Line-1])
+			lines[e.Line-1] = rewriteInstanceType(old, e.NewType) + eol
+		}
+		if err := os.WriteFile(file, []byte(strings.Join(lines, "")), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("failed to write %s: %w", file, err)
+		}
+	}
+
+	return nil
+}
+
+func rewriteInstanceType(line, newType string) string {
+	return instanceTypeRe.ReplaceAllString(line, "${1}"+newType+"${3}")
+}
@@ -0,0 +1,184 @@
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	modulePathRe   = regexp.MustCompile(`^module\.([^.\[]+)(?:\[[^\]]*\])?\.`)
+	moduleHeaderRe = regexp.MustCompile(`^\s*module\s+"([^"]+)"\s*\{`)
+	moduleSourceRe = regexp.MustCompile(`^\s*source\s*=\s*"([^"]*)"`)
+	moduleManifest = filepath.Join(".terraform", "modules", "modules.json")
+)
+
+// modulePath returns the module call names in a resource address, e.g.
+// module.app.module.db["a"].aws_instance.x -> [app db]
+func modulePath(address string) []string {
+	var path []string
+	for {
+		m := modulePathRe.FindStringSubmatch(address)
+		if m == nil {
+			return path
+		}
+		path = append(path, m[1])
+		address = address[len(m[0]):]
+	}
+}
+
+// terraformModule is the code of one module directory: its mapped resource
+// blocks by "type.name" and the sources of the modules it calls
+type terraformModule struct {
+	blocks  map[string][]tfBlock
+	sources map[string]string
+}
+
+// moduleResolver finds and scans the directory holding each module path,
+// using the module manifest written by `terraform init` when present and
+// local module sources otherwise
+type moduleResolver struct {
+	root     string
+	manifest map[string]string // "app.db" -> directory
+	scanned  map[string]terraformModule
+}
+
+func newModuleResolver(root string) (*moduleResolver, error) {
+	r := &moduleResolver{root: root, manifest: map[string]string{}, scanned: map[string]terraformModule{}}
+
+	file, err := os.ReadFile(filepath.Join(root, moduleManifest))
+	if os.IsNotExist(err) {
+		return r, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read module manifest: %w", err)
+	}
+	var manifest struct {
+		Modules []struct {
+			Key string `json:"Key"`
+			Dir string `json:"Dir"`
+		} `json:"Modules"`
+	}
+	if err := json.Unmarshal(file, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to parse module manifest: %w", err)
+	}
+	for _, m := range manifest.Modules {
+		if m.Key != "" {
+			r.manifest[m.Key] = filepath.Join(root, filepath.FromSlash(m.Dir))
+		}
+	}
+	return r, nil
+}
+
+// module returns the scanned code of the module at path; the root module is
+// the empty path
+func (r *moduleResolver) module(path []string) (terraformModule, error) {
+	dir, err := r.dir(path)
+	if err != nil {
+		return terraformModule{}, err
+	}
+	return r.scan(dir)
+}
+
+// dir resolves a module path to its directory
+func (r *moduleResolver) dir(path []string) (string, error) {
+	if dir, ok := r.manifest[strings.Join(path, ".")]; ok {
+		return dir, nil
+	}
+
+	dir := r.root
+	for i, name := range path {
+		parent, err := r.scan(dir)
+		if err != nil {
+			return "", err
+		}
+		source, ok := parent.sources[name]
+		if !ok {
+			return "", fmt.Errorf("module.%s is not called from %s", strings.Join(path[:i+1], ".module."), dir)
+		}
+		if !strings.HasPrefix(source, "./") && !strings.HasPrefix(source, "../") {
+			return "", fmt.Errorf("module.%s has non-local source %q; run terraform init so %s maps it",
+				strings.Join(path[:i+1], ".module."), source, filepath.ToSlash(moduleManifest))
+		}
+		dir = filepath.Join(dir, filepath.FromSlash(source))
+	}
+	return dir, nil
+}
+
+// scan indexes the .tf files directly in dir, as Terraform does for a module
+func (r *moduleResolver) scan(dir string) (terraformModule, error) {
+	if m, ok := r.scanned[dir]; ok {
+		return m, nil
+	}
+
+	m := terraformModule{blocks: map[string][]tfBlock{}, sources: map[string]string{}}
+	paths, err := filepath.Glob(filepath.Join(dir, "*.tf"))
+	if err != nil {
+		return m, fmt.Errorf("failed to scan Terraform files: %w", err)
+	}
+	for _, path := range paths {
+		if err := scanTerraformFile(path, m); err != nil {
+			return m, fmt.Errorf("failed to scan Terraform files: %w", err)
+		}
+	}
+
+	r.scanned[dir] = m
+	return m, nil
+}
+
+// scanTerraformFile adds a file's mapped resource blocks and module sources
+func scanTerraformFile(path string, m terraformModule) error {
+	lines, err := readLines(path)
+	if err != nil {
+		return err
+	}
+
+	var current *tfBlock
+	var key, module string
+	depth := 0
+	for i, raw := range lines {
+		line := strings.TrimRight(raw, "\r\n")
+		if depth == 0 {
+			current, module = nil, ""
+			if h := resourceHeaderRe.FindStringSubmatch(line); h != nil && isMappedType(h[1]) {
+				current = &tfBlock{file: path}
+				key = h[1] + "." + h[2]
+			} else if h := moduleHeaderRe.FindStringSubmatch(line); h != nil {
+				module = h[1]
+			}
+			depth = braceDelta(line)
+			if depth < 0 {
+				depth = 0
+			}
+			continue
+		}
+
+		// Only top-level attributes of the block count
+		if depth == 1 {
+			trimmed := strings.TrimSpace(line)
+			if current != nil && strings.HasPrefix(trimmed, "instance_type") {
+				if t := instanceTypeRe.FindStringSubmatch(line); t != nil {
+					current.typeLine = i + 1
+					current.instanceType = t[2]
+				}
+			}
+			if module != "" {
+				if s := moduleSourceRe.FindStringSubmatch(line); s != nil {
+					m.sources[module] = s[1]
+				}
+			}
+		}
+
+		depth += braceDelta(line)
+		if depth <= 0 {
+			if current != nil {
+				m.blocks[key] = append(m.blocks[key], *current)
+			}
+			depth = 0
+		}
+	}
+	return nil
+}
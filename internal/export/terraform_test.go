@@ -0,0 +1,139 @@
+package export
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// writeFiles creates files under dir, making parent directories as needed
+func writeFiles(t *testing.T, dir string, files map[string]string) {
+	t.Helper()
+	for name, content := range files {
+		path := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	tests := []struct {
+		address string
+		want    string
+	}{
+		{"aws_instance.web", ""},
+		{"aws_instance.web[0]", ""},
+		{"module.app.aws_instance.web", "app"},
+		{`module.app["eu"].module.db[1].aws_instance.web["a"]`, "app.db"},
+	}
+	for _, tt := range tests {
+		if got := strings.Join(modulePath(tt.address), "."); got != tt.want {
+			t.Errorf("modulePath(%q) = %q, want %q", tt.address, got, tt.want)
+		}
+	}
+}
+
+func TestPlanTerraformModules(t *testing.T) {
+	dir := t.TempDir()
+	block := "resource \"aws_instance\" \"web\" {\n  instance_type = \"%s\"\n}\n"
+	writeFiles(t, dir, map[string]string{
+		"main.tf":                strings.Replace(block, "%s", "t3.large", 1) + "module \"app\" {\n  source = \"./modules/app\"\n}\nmodule \"remote\" {\n  source = \"terraform-aws-modules/ec2-instance/aws\"\n}\n",
+		"modules/app/main.tf":    strings.Replace(block, "%s", "m5.large", 1),
+		"modules/unused/main.tf": strings.Replace(block, "%s", "m5.large", 1),
+	})
+
+	resources := []TerraformResource{
+		{Address: "aws_instance.web", Type: "aws_instance", Name: "web", ID: "i-root"},
+		{Address: "module.app.aws_instance.web", Type: "aws_instance", Name: "web", ID: "i-app"},
+		{Address: "module.remote.aws_instance.web", Type: "aws_instance", Name: "web", ID: "i-remote"},
+	}
+	recs := []model.Recommendation{
+		{InstanceID: "i-root", InstanceType: "t3.large", SuggestedType: "t3.medium"},
+		{InstanceID: "i-app", InstanceType: "m5.large", SuggestedType: "m6i.large"},
+		{InstanceID: "i-remote", InstanceType: "m5.large", SuggestedType: "m6i.large"},
+	}
+
+	plan, err := PlanTerraform(recs, nil, resources, dir)
+	if err != nil {
+		t.Fatalf("PlanTerraform: %v", err)
+	}
+
+	files := map[string]string{}
+	for _, e := range plan.Edits {
+		rel, _ := filepath.Rel(dir, e.File)
+		files[e.Address] = filepath.ToSlash(rel)
+	}
+	want := map[string]string{
+		"aws_instance.web":            "main.tf",
+		"module.app.aws_instance.web": "modules/app/main.tf",
+	}
+	for addr, file := range want {
+		if files[addr] != file {
+			t.Errorf("%s edited in %q, want %q", addr, files[addr], file)
+		}
+	}
+	if len(plan.Edits) != len(want) {
+		t.Errorf("got %d edits, want %d: %+v", len(plan.Edits), len(want), plan.Edits)
+	}
+	if len(plan.Unmapped) != 1 || plan.Unmapped[0].InstanceID != "i-remote" || !strings.Contains(plan.Unmapped[0].Reason, "non-local source") {
+		t.Errorf("expected the remote module instance unmapped for its source, got %+v", plan.Unmapped)
+	}
+
+	// With the module manifest from terraform init, the remote module resolves
+	writeFiles(t, dir, map[string]string{
+		".terraform/modules/modules.json":          `{"Modules":[{"Key":"","Dir":"."},{"Key":"remote","Dir":".terraform/modules/remote"}]}`,
+		".terraform/modules/remote/main.tf":        strings.Replace(block, "%s", "m5.large", 1),
+		".terraform/modules/remote/unrelated/x.tf": strings.Replace(block, "%s", "c5.large", 1),
+	})
+	plan, err = PlanTerraform(recs, nil, resources, dir)
+	if err != nil {
+		t.Fatalf("PlanTerraform with manifest: %v", err)
+	}
+	if len(plan.Edits) != 3 || len(plan.Unmapped) != 0 {
+		t.Errorf("with manifest got %d edits and %+v unmapped, want 3 edits", len(plan.Edits), plan.Unmapped)
+	}
+}
+
+func TestTerraformApplyKeepsLineEndings(t *testing.T) {
+	dir := t.TempDir()
+	content := "# web\r\nresource \"aws_instance\" \"web\" {\r\n  ami           = \"ami-1\"\r\n  instance_type = \"t3.large\"\r\n}\r\n"
+	writeFiles(t, dir, map[string]string{"main.tf": content})
+
+	resources := []TerraformResource{{Address: "aws_instance.web", Type: "aws_instance", Name: "web", ID: "i-1"}}
+	recs := []model.Recommendation{{InstanceID: "i-1", InstanceType: "t3.large", SuggestedType: "t3.medium"}}
+
+	plan, err := PlanTerraform(recs, nil, resources, dir)
+	if err != nil || len(plan.Edits) != 1 {
+		t.Fatalf("PlanTerraform = %+v, %v; want one edit", plan, err)
+	}
+	if plan.Edits[0].Line != 4 {
+		t.Errorf("edit on line %d, want 4", plan.Edits[0].Line)
+	}
+
+	patch, err := plan.Patch(dir)
+	if err != nil {
+		t.Fatalf("Patch: %v", err)
+	}
+	if !strings.Contains(patch, "-  instance_type = \"t3.large\"\r\n+  instance_type = \"t3.medium\"\r\n") {
+		t.Errorf("patch lines should keep CRLF endings, got %q", patch)
+	}
+
+	if err := plan.Apply(); err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+	got, err := os.ReadFile(filepath.Join(dir, "main.tf"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := strings.Replace(content, "t3.large", "t3.medium", 1)
+	if string(got) != want {
+		t.Errorf("Apply wrote %q, want %q", got, want)
+	}
+}
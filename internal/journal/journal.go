@@ -0,0 +1,181 @@
+package journal
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/config"
+)
+
+// Change states recorded as a resize progresses
+const (
+	StatePending    = "pending"
+	StateStopping   = "stopping"
+	StateModifying  = "modifying"
+	StateStarting   = "starting"
+	StateCompleted  = "completed"
+	StateFailed     = "failed"
+	StateRolledBack = "rolled-back"
+)
+
+// Change kinds
+const (
+	KindApply    = "apply"
+	KindRollback = "rollback"
+)
+
+// Transition records a single state change of a journaled change.
+type Transition struct {
+	State string    `json:"state"`
+	At    time.Time `json:"at"`
+	Note  string    `json:"note,omitempty"`
+}
+
+// Change describes one instance type mutation performed against AWS.
+type Change struct {
+	ID           string       `json:"id"`
+	Kind         string       `json:"kind"`
+	InstanceID   string       `json:"instance_id"`
+	OriginalType string       `json:"original_type"`
+	NewType      string       `json:"new_type"`
+	Operator     string       `json:"operator"`
+	RollbackOf   string       `json:"rollback_of,omitempty"`
+	State        string       `json:"state"`
+	CreatedAt    time.Time    `json:"created_at"`
+	UpdatedAt    time.Time    `json:"updated_at"`
+	Transitions  []Transition `json:"transitions"`
+	Error        string       `json:"error,omitempty"`
+}
+
+// Journal is an append-only record of changes, persisted as JSON.
+type Journal struct {
+	path    string
+	Changes []Change `json:"changes"`
+}
+
+// DefaultPath returns the journal location inside the app data directory.
+func DefaultPath() (string, error) {
+	dir, err := config.Dir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "journal.json"), nil
+}
+
+// Open loads the journal at path, returning an empty journal if the file does not exist.
+func Open(path string) (*Journal, error) {
+	j := &Journal{path: path}
+
+	bytes, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return j, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read journal: %w", err)
+	}
+
+	if err := json.Unmarshal(bytes, j); err != nil {
+		return nil, fmt.Errorf("failed to parse journal: %w", err)
+	}
+
+	return j, nil
+}
+
+// Begin records a new pending change and persists it before any mutation happens.
+func (j *Journal) Begin(kind, instanceID, originalType, newType, operator, rollbackOf string) (Change, error) {
+	id, err := newChangeID()
+	if err != nil {
+		return Change{}, err
+	}
+
+	now := time.Now().UTC()
+	c := Change{
+		ID:           id,
+		Kind:         kind,
+		InstanceID:   instanceID,
+		OriginalType: originalType,
+		NewType:      newType,
+		Operator:     operator,
+		RollbackOf:   rollbackOf,
+		State:        StatePending,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		Transitions:  []Transition{{State: StatePending, At: now}},
+	}
+
+	j.Changes = append(j.Changes, c)
+	return c, j.save()
+}
+
+// Transition moves a change to a new state and persists the journal.
+func (j *Journal) Transition(id, state, note string) error {
+	for i := range j.Changes {
+		if j.Changes[i].ID != id {
+			continue
+		}
+
+		now := time.Now().UTC()
+		j.Changes[i].State = state
+		j.Changes[i].UpdatedAt = now
+		j.Changes[i].Transitions = append(j.Changes[i].Transitions, Transition{State: state, At: now, Note: note})
+		if state == StateFailed {
+			j.Changes[i].Error = note
+		}
+		return j.save()
+	}
+
+	return fmt.Errorf("change %s not found in journal", id)
+}
+
+// Modified reports whether the type change itself went through: the change
+// completed, or it failed after the modify step (e.g. the instance would not
+// start on the new type).
+func (c Change) Modified() bool {
+	if c.State == StateCompleted {
+		return true
+	}
+	for _, tr := range c.Transitions {
+		if tr.State == StateStarting {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns the file the journal is persisted to.
+func (j *Journal) Path() string {
+	return j.path
+}
+
+// Get returns the change with the given ID.
+func (j *Journal) Get(id string) (Change, bool) {
+	for _, c := range j.Changes {
+		if c.ID == id {
+			return c, true
+		}
+	}
+	return Change{}, false
+}
+
+// save writes the journal to disk
+func (j *Journal) save() error {
+	bytes, err := json.MarshalIndent(j, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(j.path, bytes, 0600)
+}
+
+// newChangeID builds a sortable, unique change identifier
+func newChangeID() (string, error) {
+	b := make([]byte, 3)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate change ID: %w", err)
+	}
+	return fmt.Sprintf("chg-%s-%s", time.Now().UTC().Format("20060102T150405"), hex.EncodeToString(b)), nil
+}
@@ -1,6 +1,9 @@
 package logging
 
-import "fmt"
+import (
+	"fmt"
+	"os"
+)
 
 var Verbose bool
 
@@ -9,17 +12,17 @@ func Info(msg string) {
 }
 
 func Warn(msg string) {
-	fmt.Println("[warning]", msg)
+	fmt.Fprintln(os.Stderr, "[warning]", msg)
 }
 
 func Debug(msg string) {
 	if Verbose {
-		fmt.Println("[debug]", msg)
+		fmt.Fprintln(os.Stderr, "[debug]", msg)
 	}
 }
 
 func DebugErr(context string, err error) {
 	if Verbose && err != nil {
-		fmt.Printf("[debug] %s: %v\n", context, err)
+		fmt.Fprintf(os.Stderr, "[debug] %s: %v\n", context, err)
 	}
-}
\ No newline at end of file
+}
@@ -0,0 +1,113 @@
+package model
+
+import "time"
+
+// ReservedInstance is an active EC2 Reserved Instance purchase.
+type ReservedInstance struct {
+	ID               string    `json:"id"`
+	InstanceType     string    `json:"instance_type"`
+	Count            int       `json:"count"`
+	AvailabilityZone string    `json:"availability_zone,omitempty"` // zonal RIs only; regional RIs are size-flexible
+	Platform         string    `json:"platform"`                    // product description, e.g. "Linux/UNIX"
+	OfferingClass    string    `json:"offering_class"`              // standard | convertible
+	OfferingType     string    `json:"offering_type"`               // All Upfront | Partial Upfront | No Upfront
+	FixedPrice       float64   `json:"fixed_price"`                 // upfront, USD
+	HourlyPrice      float64   `json:"hourly_price"`                // recurring charge per instance hour, USD
+	Start            time.Time `json:"start"`
+	End              time.Time `json:"end"`
+}
+
+// SavingsPlan is an active Compute or EC2 Instance Savings Plan.
+type SavingsPlan struct {
+	ID             string    `json:"id"`                        // ARN
+	Type           string    `json:"type"`                      // Compute | EC2Instance
+	InstanceFamily string    `json:"instance_family,omitempty"` // EC2Instance plans only
+	Region         string    `json:"region,omitempty"`
+	Commitment     float64   `json:"commitment"` // USD per hour
+	TermYears      int       `json:"term_years"`
+	PaymentOption  string    `json:"payment_option"`
+	Start          time.Time `json:"start"`
+	End            time.Time `json:"end"`
+}
+
+// CommitmentUtilisation is Cost Explorer's observed utilisation of one RI or Savings Plan.
+type CommitmentUtilisation struct {
+	ID             string  `json:"id"` // RI ID or Savings Plan ARN
+	UtilisationPct float64 `json:"utilisation_pct"`
+}
+
+// FamilyCoverage is Cost Explorer's commitment coverage for one instance family.
+type FamilyCoverage struct {
+	Family        string  `json:"family"`
+	RICoveragePct float64 `json:"ri_coverage_pct"` // running hours covered by RIs
+	SPCoveragePct float64 `json:"sp_coverage_pct"` // eligible spend covered by Savings Plans
+}
+
+// CommitmentCover is the share of an instance's on-demand usage already paid for by commitments.
+type CommitmentCover struct {
+	CoveredPct   float64  `json:"covered_pct"`
+	RIs          []string `json:"reserved_instances,omitempty"`
+	SavingsPlans []string `json:"savings_plans,omitempty"`
+}
+
+// FamilyCommitment summarises the commitments that apply to one instance
+// family and how many of their hours go unused. Compute Savings Plans are
+// reported under the family "compute".
+type FamilyCommitment struct {
+	Family            string  `json:"family"`
+	ReservedInstances int     `json:"reserved_instances"` // instance count across RIs
+	RIHours           float64 `json:"ri_hours"`           // purchased instance-hours per month
+	UnusedRIHours     float64 `json:"unused_ri_hours"`
+	RIUtilisationPct  float64 `json:"ri_utilisation_pct"`
+	SavingsPlans      int     `json:"savings_plans"`
+	SPCommitment      float64 `json:"sp_commitment"`   // USD per hour
+	UnusedSPHours     float64 `json:"unused_sp_hours"` // hours per month the commitment goes unused
+	SPUtilisationPct  float64 `json:"sp_utilisation_pct"`
+	RICoveragePct     float64 `json:"ri_coverage_pct"`
+	SPCoveragePct     float64 `json:"sp_coverage_pct"`
+	Source            string  `json:"source"` // cost-explorer | estimated | mixed
+}
+
+// FamilyDailyCost is the daily on-demand EC2 compute cost of one instance family.
+type FamilyDailyCost struct {
+	Family string    `json:"family"`
+	Daily  []float64 `json:"daily"` // USD per day, oldest first
+}
+
+// FamilyBaseline is the steady-state on-demand spend of one family, before
+// and after the recommended changes.
+type FamilyBaseline struct {
+	Family       string  `json:"family"`
+	Historical   float64 `json:"historical_hourly"`  // low percentile of daily on-demand cost, USD per hour
+	RightSizing  float64 `json:"rightsizing_hourly"` // change from recommendations, USD per hour
+	BaselineRate float64 `json:"baseline_hourly"`    // projected steady-state on-demand spend, USD per hour
+}
+
+// SavingsPlanOption is a proposed Savings Plan purchase.
+type SavingsPlanOption struct {
+	Type              string  `json:"type"`                      // Compute | EC2Instance
+	Family            string  `json:"instance_family,omitempty"` // EC2Instance plans only
+	TermYears         int     `json:"term_years"`
+	DiscountPct       float64 `json:"discount_pct"`
+	HourlyCommitment  float64 `json:"hourly_commitment"` // USD per hour
+	MonthlyCommitment float64 `json:"monthly_commitment"`
+	MonthlySaving     float64 `json:"monthly_saving"` // vs paying on-demand for the baseline
+	TermSaving        float64 `json:"term_saving"`
+	BreakEvenMonth    float64 `json:"break_even_month"`     // covered usage must last this long for the plan to pay off
+	BreakEvenUsagePct float64 `json:"break_even_usage_pct"` // or stay above this share of the baseline for the whole term
+	Alternative       string  `json:"alternative"`          // options sharing this are bought together; different alternatives cover the same usage
+}
+
+// CommitmentExpiry is an RI or Savings Plan ending soon and what the usage it
+// covers today would cost on demand once it has ended.
+type CommitmentExpiry struct {
+	ID              string    `json:"id"`
+	Kind            string    `json:"kind"`        // reserved-instance | savings-plan
+	Description     string    `json:"description"` // e.g. "1x m5.large" or "Compute 3y $0.050/h"
+	End             time.Time `json:"end"`
+	DaysLeft        int       `json:"days_left"`
+	Instances       []string  `json:"instances,omitempty"` // running instances it covers
+	CoveredOnDemand float64   `json:"covered_on_demand"`   // on-demand price of the covered usage, USD per month
+	CommitmentCost  float64   `json:"commitment_cost"`     // effective cost of the commitment, USD per month
+	MonthlyIncrease float64   `json:"monthly_increase"`    // negative when the commitment is mostly unused
+}
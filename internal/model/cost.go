@@ -1,8 +1,14 @@
 package model
 
 type CostData struct {
-	InstanceID string `json:"instance_id"`
+	InstanceID  string  `json:"instance_id"`
 	MonthlyCost float64 `json:"monthly_cost"`
-	HourlyCost float64 `json:"hourly_cost"`
+	HourlyCost  float64 `json:"hourly_cost"`
+}
 
-}
\ No newline at end of file
+// TagCost is billed cost attributed to one combination of tag values.
+// A tag missing from the resources is represented by an empty value.
+type TagCost struct {
+	Tags        map[string]string `json:"tags"`
+	MonthlyCost float64           `json:"monthly_cost"`
+}
@@ -1,7 +1,36 @@
 package model
 
-// CPUSampleSeries represents a slice of CPU utilisation samples.
+import "time"
+
+// CPUSampleSeries represents a slice of CPU utilisation samples, oldest first.
 type CPUSampleSeries struct {
+	InstanceID    string
+	Samples       []float64
+	Timestamps    []time.Time // start of each sample's period, parallel to Samples
+	PeriodSeconds int         // time covered by each sample
+	WindowSeconds int         // time the query asked for; with PeriodSeconds, sets the samples expected
+}
+
+// MemorySampleSeries represents memory utilisation samples (percent used)
+// published by the CloudWatch agent.
+type MemorySampleSeries struct {
 	InstanceID string
 	Samples    []float64
-}
\ No newline at end of file
+}
+
+// NetworkActivity summarises network traffic and connections over a window.
+type NetworkActivity struct {
+	InstanceID      string    `json:"instance_id"`
+	NetworkInBytes  float64   `json:"network_in_bytes"`
+	NetworkOutBytes float64   `json:"network_out_bytes"`
+	PeakBytesPerSec float64   `json:"peak_bytes_per_sec"` // busiest five minutes, in+out
+	Connections     []float64 `json:"connections"`        // established TCP connections; empty without the CloudWatch agent
+}
+
+// CreditMetrics holds hourly CPU credit metrics for a burstable instance.
+type CreditMetrics struct {
+	InstanceID     string    `json:"instance_id"`
+	Balance        []float64 `json:"balance"`         // CPUCreditBalance, hourly average
+	Usage          []float64 `json:"usage"`           // CPUCreditUsage, credits spent per hour
+	SurplusCharged []float64 `json:"surplus_charged"` // CPUSurplusCreditsCharged, credits billed per hour in unlimited mode
+}
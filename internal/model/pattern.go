@@ -0,0 +1,20 @@
+package model
+
+// Seasonality describes cycles and recurring peaks found in hourly CPU history.
+type Seasonality struct {
+	Days        float64         `json:"days"`         // history analysed
+	DailyACF    float64         `json:"daily_acf"`    // autocorrelation at a 24 hour lag
+	WeeklyACF   float64         `json:"weekly_acf"`   // autocorrelation at a 168 hour lag
+	Daily       bool            `json:"daily"`        // usage repeats every day
+	Weekly      bool            `json:"weekly"`       // weekdays and weekends differ
+	PeriodHours int             `json:"period_hours"` // longest cycle detected, 0 when none
+	Peaks       []RecurringPeak `json:"peaks,omitempty"`
+}
+
+// RecurringPeak is a CPU peak that comes back on a schedule.
+type RecurringPeak struct {
+	Pattern     string  `json:"pattern"` // daily | weekly | month-end
+	When        string  `json:"when"`    // e.g. "02:00 UTC", "MON 02:00 UTC", "last days of the month"
+	Occurrences int     `json:"occurrences"`
+	PeakCPU     float64 `json:"peak_cpu"`
+}
@@ -0,0 +1,13 @@
+package model
+
+// DataQuality compares the CPU datapoints received with those expected from
+// the metric period and analysis window.
+type DataQuality struct {
+	ExpectedPoints    int     `json:"expected_points"`
+	ReceivedPoints    int     `json:"received_points"`
+	CoveragePct       float64 `json:"coverage_pct"` // received / expected, capped at 100
+	Gaps              int     `json:"gaps"`         // runs of one or more missing periods
+	LongestGapMinutes int     `json:"longest_gap_minutes"`
+	FlatLine          bool    `json:"flat_line"` // the same value repeated for most of the window
+	FlatLineValue     float64 `json:"flat_line_value,omitempty"`
+}
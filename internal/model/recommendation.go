@@ -2,15 +2,32 @@ package model
 
 // Recommendation describes optimisation advice for a single EC2 instance.
 type Recommendation struct {
-	InstanceID      string  `json:"instance_id"`
-	InstanceType    string  `json:"instance_type"`
-	State           string  `json:"state"`
-	AvgCPU          float64 `json:"avg_cpu"`
-	PeakCPU         float64 `json:"peak_cpu"`
-	MonthlyCost     float64 `json:"monthly_cost"`
-	HourlyCost      float64 `json:"hourly_cost"`
-	Action          string  `json:"action"`           // e.g. "Downsize", "Upsize", "Keep as-is"
-	SuggestedType   string  `json:"suggested_type"`   // e.g. "t3.nano"
-	EstimatedSaving float64 `json:"estimated_saving"` // per month, rough estimate
-	Reason          string  `json:"reason"`
+	InstanceID      string           `json:"instance_id"`
+	InstanceType    string           `json:"instance_type"`
+	State           string           `json:"state"`
+	AvgCPU          float64          `json:"avg_cpu"`
+	PeakCPU         float64          `json:"peak_cpu"`
+	MonthlyCost     float64          `json:"monthly_cost"`
+	HourlyCost      float64          `json:"hourly_cost"`
+	Action          string           `json:"action"`           // e.g. "Downsize", "Upsize", "Keep as-is"
+	SuggestedType   string           `json:"suggested_type"`   // e.g. "t3.nano"
+	EstimatedSaving float64          `json:"estimated_saving"` // per month, rough estimate
+	Reason          string           `json:"reason"`
+	EBSMonthlyCost  float64          `json:"ebs_monthly_cost,omitempty"` // attached storage, reported for stopped instances
+	Projection      *Projection      `json:"projection,omitempty"`       // expected CPU on SuggestedType
+	Spot            *SpotAdvice      `json:"spot,omitempty"`             // set when spot advice is requested
+	Commitment      *CommitmentCover `json:"commitment,omitempty"`       // usage already covered by RIs or Savings Plans
+	Pattern         *Seasonality     `json:"pattern,omitempty"`          // cycles and recurring peaks in long CPU history
+	DataQuality     *DataQuality     `json:"data_quality,omitempty"`     // CPU datapoints received against expected
+	Confidence      float64          `json:"confidence"`                 // 0-1, how far the action can be trusted
+	ConfidenceLevel string           `json:"confidence_level"`           // high | medium | low
+	ConfidenceNotes []string         `json:"confidence_notes,omitempty"` // what held the score down
+}
+
+// Projection is the CPU utilisation expected after moving to another type.
+type Projection struct {
+	InstanceType string  `json:"instance_type"`
+	AvgCPU       float64 `json:"avg_cpu"`
+	P95CPU       float64 `json:"p95_cpu"`
+	PeakCPU      float64 `json:"peak_cpu"`
 }
@@ -0,0 +1,46 @@
+package model
+
+import "time"
+
+// CPUPoint is one timestamped CPU utilisation average.
+type CPUPoint struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// CPUHistory is hourly CPU utilisation over several days, oldest first.
+type CPUHistory struct {
+	InstanceID string
+	Points     []CPUPoint
+}
+
+// ScheduleAdvice proposes running an instance only during the hours it is
+// used. Days use three-letter names (MON..SUN); hours are in Timezone and
+// StopHour 24 means midnight at the end of the day.
+type ScheduleAdvice struct {
+	InstanceID          string   `json:"instance_id"`
+	InstanceType        string   `json:"instance_type"`
+	Environment         string   `json:"environment"`
+	AutoScalingGroup    string   `json:"auto_scaling_group,omitempty"`
+	Suitable            bool     `json:"suitable"`
+	Timezone            string   `json:"timezone"`
+	Days                []string `json:"days,omitempty"`
+	StartHour           int      `json:"start_hour"`
+	StopHour            int      `json:"stop_hour"`
+	RunningHoursPerWeek int      `json:"running_hours_per_week"`
+	BusinessHoursCPU    float64  `json:"business_hours_cpu"`   // mean CPU inside the window
+	OffHoursCPU         float64  `json:"off_hours_cpu"`        // mean CPU outside the window
+	OffHoursActivePct   float64  `json:"off_hours_active_pct"` // off-window hours with real activity
+	MonthlyCost         float64  `json:"monthly_cost"`
+	MonthlySaving       float64  `json:"monthly_saving"`
+	Reason              string   `json:"reason"`
+}
+
+// AutoScalingGroup is the capacity an Auto Scaling group is configured with,
+// recorded so an off-hours schedule can scale it back to the same size.
+type AutoScalingGroup struct {
+	Name            string `json:"name"`
+	MinSize         int32  `json:"min_size"`
+	MaxSize         int32  `json:"max_size"`
+	DesiredCapacity int32  `json:"desired_capacity"`
+}
@@ -0,0 +1,18 @@
+package model
+
+// Simulation is the result of a what-if change of instance type.
+type Simulation struct {
+	InstanceID           string      `json:"instance_id"`
+	CurrentType          string      `json:"current_type"`
+	TargetType           string      `json:"target_type"`
+	CurrentCPU           *Projection `json:"current_cpu,omitempty"`   // observed avg/p95/peak
+	ProjectedCPU         *Projection `json:"projected_cpu,omitempty"` // rescaled onto TargetType
+	PeakMemoryPercent    float64     `json:"peak_memory_percent,omitempty"`
+	ProjectedMemory      float64     `json:"projected_memory_percent,omitempty"`
+	MemoryFits           *bool       `json:"memory_fits,omitempty"` // nil without memory metrics
+	MonthlyCost          float64     `json:"monthly_cost"`
+	ProjectedMonthlyCost float64     `json:"projected_monthly_cost"`
+	MonthlyDelta         float64     `json:"monthly_delta"` // negative is a saving
+	Risk                 string      `json:"risk"`          // low | medium | high
+	Notes                []string    `json:"notes,omitempty"`
+}
@@ -0,0 +1,25 @@
+package model
+
+import "time"
+
+// SpotPrice is one point of EC2 spot price history.
+type SpotPrice struct {
+	InstanceType       string    `json:"instance_type"`
+	AvailabilityZone   string    `json:"availability_zone"`
+	ProductDescription string    `json:"product_description,omitempty"` // e.g. "Linux/UNIX", "Windows"
+	Price              float64   `json:"price"`                         // USD per hour, licence included
+	Timestamp          time.Time `json:"timestamp"`
+}
+
+// SpotAdvice scores how well an instance would run on Spot.
+type SpotAdvice struct {
+	InstanceType     string   `json:"instance_type"`     // type the advice applies to (the suggested type when there is one)
+	Score            int      `json:"score"`             // 0-100 suitability
+	Suitable         bool     `json:"suitable"`          // score high enough and interruption risk not high
+	SpotHourlyPrice  float64  `json:"spot_hourly_price"` // mean over the history window
+	DiscountPercent  float64  `json:"discount_percent"`  // vs on-demand
+	ExpectedSaving   float64  `json:"expected_saving"`   // per month, on top of the recommendation
+	InterruptionRisk string   `json:"interruption_risk"` // low | medium | high
+	Pools            int      `json:"pools"`             // interchangeable instance types for diversification
+	Factors          []string `json:"factors"`
+}
@@ -0,0 +1,33 @@
+package model
+
+// Summary aggregates a set of recommendations into fleet-level totals.
+type Summary struct {
+	InstancesAnalysed    int                `json:"instances_analysed"`
+	TotalMonthlyCost     float64            `json:"total_monthly_cost"`
+	TotalEstimatedSaving float64            `json:"total_estimated_saving"`
+	SavingPercent        float64            `json:"saving_percent"`
+	ActionCounts         map[string]int     `json:"action_counts"`
+	UnderutilisedCount   int                `json:"underutilised_count"`
+	UnderutilisedPercent float64            `json:"underutilised_percent"`
+	TopSavers            []Saver            `json:"top_savers"`
+	ByFamily             []Breakdown        `json:"by_family"`
+	ByState              []Breakdown        `json:"by_state"`
+	ExpiringCommitments  []CommitmentExpiry `json:"expiring_commitments,omitempty"`
+}
+
+// Saver is one of the largest individual savings opportunities.
+type Saver struct {
+	InstanceID      string  `json:"instance_id"`
+	InstanceType    string  `json:"instance_type"`
+	Action          string  `json:"action"`
+	SuggestedType   string  `json:"suggested_type"`
+	EstimatedSaving float64 `json:"estimated_saving"`
+}
+
+// Breakdown totals cost and savings for one group of instances.
+type Breakdown struct {
+	Key             string  `json:"key"`
+	Instances       int     `json:"instances"`
+	MonthlyCost     float64 `json:"monthly_cost"`
+	EstimatedSaving float64 `json:"estimated_saving"`
+}
@@ -1,9 +1,36 @@
 package model
 
+import "time"
+
 type EC2Instance struct {
-	ID string `json:"id"`
-	InstanceType string `json:"instance_type"`
-	State string `json:"state"`
-	Tags map[string]string `json:"tags"`
+	ID                    string            `json:"id"`
+	InstanceType          string            `json:"instance_type"`
+	State                 string            `json:"state"`
+	Tags                  map[string]string `json:"tags"`
+	AccountID             string            `json:"account_id,omitempty"`
+	Region                string            `json:"region,omitempty"`
+	LaunchTime            time.Time         `json:"launch_time,omitzero"`
+	StateTransitionReason string            `json:"state_transition_reason,omitempty"`
+	AvailabilityZone      string            `json:"availability_zone,omitempty"`
+	Platform              string            `json:"platform,omitempty"`     // e.g. "Linux/UNIX", "Windows"
+	Architecture          string            `json:"architecture,omitempty"` // e.g. "x86_64", "arm64"
+	Tenancy               string            `json:"tenancy,omitempty"`      // default | dedicated | host
+	Lifecycle             string            `json:"lifecycle,omitempty"`    // on-demand | spot | scheduled
+	AutoScalingGroup      string            `json:"auto_scaling_group,omitempty"`
+	VpcID                 string            `json:"vpc_id,omitempty"`
+	SubnetID              string            `json:"subnet_id,omitempty"`
+	IAMInstanceProfile    string            `json:"iam_instance_profile,omitempty"`
+	ImageID               string            `json:"image_id,omitempty"`
+	ImageName             string            `json:"image_name,omitempty"`
+	Volumes               []EBSVolume       `json:"volumes,omitempty"`
 }
 
+// EBSVolume is an EBS volume attached to an instance.
+type EBSVolume struct {
+	VolumeID   string `json:"volume_id"`
+	DeviceName string `json:"device_name,omitempty"`
+	VolumeType string `json:"volume_type"`
+	SizeGiB    int32  `json:"size_gib"`
+	Iops       int32  `json:"iops,omitempty"`
+	Throughput int32  `json:"throughput,omitempty"`
+}
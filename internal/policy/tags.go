@@ -0,0 +1,174 @@
+package policy
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Violation rules
+const (
+	RuleMissing    = "missing"
+	RuleNotAllowed = "not-allowed"
+	RulePattern    = "pattern"
+)
+
+// TagPolicy describes the tags every instance must carry.
+type TagPolicy struct {
+	Required      []string            `json:"required"`
+	AllowedValues map[string][]string `json:"allowed_values"`
+	Patterns      map[string]string   `json:"patterns"`
+
+	compiled map[string]*regexp.Regexp
+}
+
+// Violation is one instance failing one policy rule.
+type Violation struct {
+	InstanceID string `json:"instance_id"`
+	AccountID  string `json:"account_id"`
+	Region     string `json:"region"`
+	Key        string `json:"key"`
+	Rule       string `json:"rule"`
+	Value      string `json:"value,omitempty"`
+	Message    string `json:"message"`
+}
+
+// Compliance summarises policy results for one account and region.
+type Compliance struct {
+	AccountID string  `json:"account_id"`
+	Region    string  `json:"region"`
+	Instances int     `json:"instances"`
+	Compliant int     `json:"compliant"`
+	Percent   float64 `json:"percent"`
+}
+
+// LoadTagPolicy reads a JSON tag policy and compiles its patterns.
+func LoadTagPolicy(path string) (TagPolicy, error) {
+	file, err := os.ReadFile(path)
+	if err != nil {
+		return TagPolicy{}, fmt.Errorf("failed to read tag policy: %w", err)
+	}
+
+	var p TagPolicy
+	if err := json.Unmarshal(file, &p); err != nil {
+		return TagPolicy{}, fmt.Errorf("failed to parse tag policy: %w", err)
+	}
+
+	p.compiled = map[string]*regexp.Regexp{}
+	for key, pattern := range p.Patterns {
+		re, err := regexp.Compile(pattern)
+		if err != nil {
+			return TagPolicy{}, fmt.Errorf("invalid pattern for tag %s: %w", key, err)
+		}
+		p.compiled[key] = re
+	}
+
+	return p, nil
+}
+
+// Evaluate returns every violation of the policy by a single instance.
+// Allowed values and patterns are only checked for tags that are present.
+func (p TagPolicy) Evaluate(inst model.EC2Instance) []Violation {
+	var out []Violation
+
+	add := func(key, rule, value, msg string) {
+		out = append(out, Violation{
+			InstanceID: inst.ID,
+			AccountID:  inst.AccountID,
+			Region:     inst.Region,
+			Key:        key,
+			Rule:       rule,
+			Value:      value,
+			Message:    msg,
+		})
+	}
+
+	for _, key := range p.Required {
+		if _, ok := inst.Tags[key]; !ok {
+			add(key, RuleMissing, "", fmt.Sprintf("required tag %s is missing", key))
+		}
+	}
+
+	for _, key := range sortedKeys(p.AllowedValues) {
+		value, ok := inst.Tags[key]
+		if !ok {
+			continue
+		}
+		allowed := p.AllowedValues[key]
+		if !contains(allowed, value) {
+			add(key, RuleNotAllowed, value, fmt.Sprintf("%s=%q is not one of: %s", key, value, strings.Join(allowed, ", ")))
+		}
+	}
+
+	for _, key := range sortedKeys(p.Patterns) {
+		value, ok := inst.Tags[key]
+		if !ok {
+			continue
+		}
+		if re := p.compiled[key]; re != nil && !re.MatchString(value) {
+			add(key, RulePattern, value, fmt.Sprintf("%s=%q does not match %s", key, value, p.Patterns[key]))
+		}
+	}
+
+	return out
+}
+
+// Check evaluates all instances and computes compliance per account and region.
+func (p TagPolicy) Check(instances []model.EC2Instance) ([]Violation, []Compliance) {
+	var violations []Violation
+	groups := map[string]*Compliance{}
+	var order []string
+
+	for _, inst := range instances {
+		key := inst.AccountID + "/" + inst.Region
+		g, ok := groups[key]
+		if !ok {
+			g = &Compliance{AccountID: inst.AccountID, Region: inst.Region}
+			groups[key] = g
+			order = append(order, key)
+		}
+
+		v := p.Evaluate(inst)
+		violations = append(violations, v...)
+
+		g.Instances++
+		if len(v) == 0 {
+			g.Compliant++
+		}
+	}
+
+	sort.Strings(order)
+	var compliance []Compliance
+	for _, key := range order {
+		g := groups[key]
+		if g.Instances > 0 {
+			g.Percent = float64(g.Compliant) / float64(g.Instances) * 100
+		}
+		compliance = append(compliance, *g)
+	}
+
+	return violations, compliance
+}
+
+func contains(xs []string, v string) bool {
+	for _, x := range xs {
+		if x == v {
+			return true
+		}
+	}
+	return false
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
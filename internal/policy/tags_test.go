@@ -0,0 +1,80 @@
+package policy
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// loadPolicy writes a policy to a temporary file and loads it
+func loadPolicy(t *testing.T, doc string) TagPolicy {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policy.json")
+	if err := os.WriteFile(path, []byte(doc), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadTagPolicy(path)
+	if err != nil {
+		t.Fatalf("LoadTagPolicy: %v", err)
+	}
+	return p
+}
+
+func TestEvaluate(t *testing.T) {
+	p := loadPolicy(t, `{
+		"required": ["Name", "Environment", "Team"],
+		"allowed_values": {"Environment": ["dev", "staging", "prod"], "Tier": ["web", "db"]},
+		"patterns": {"Name": "^[a-z0-9-]+$"}
+	}`)
+
+	tests := []struct {
+		name string
+		tags map[string]string
+		want []Violation // InstanceID, AccountID and Region are filled in below
+	}{
+		{
+			name: "compliant",
+			tags: map[string]string{"Name": "web-1", "Environment": "prod", "Team": "payments"},
+		},
+		{
+			name: "untagged",
+			want: []Violation{
+				{Key: "Name", Rule: RuleMissing, Message: "required tag Name is missing"},
+				{Key: "Environment", Rule: RuleMissing, Message: "required tag Environment is missing"},
+				{Key: "Team", Rule: RuleMissing, Message: "required tag Team is missing"},
+			},
+		},
+		{
+			name: "bad values",
+			tags: map[string]string{"Name": "Web_1", "Environment": "production", "Team": "payments", "Tier": "cache"},
+			want: []Violation{
+				{Key: "Environment", Rule: RuleNotAllowed, Value: "production", Message: `Environment="production" is not one of: dev, staging, prod`},
+				{Key: "Tier", Rule: RuleNotAllowed, Value: "cache", Message: `Tier="cache" is not one of: web, db`},
+				{Key: "Name", Rule: RulePattern, Value: "Web_1", Message: `Name="Web_1" does not match ^[a-z0-9-]+$`},
+			},
+		},
+		{
+			// Optional tags with allowed values are only checked when present
+			name: "missing tag is not also a bad value",
+			tags: map[string]string{"Name": "web-1", "Team": "payments"},
+			want: []Violation{{Key: "Environment", Rule: RuleMissing, Message: "required tag Environment is missing"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inst := model.EC2Instance{ID: "i-a", AccountID: "123456789012", Region: "eu-west-2", Tags: tt.tags}
+			got := p.Evaluate(inst)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Evaluate = %+v, want %d violations", got, len(tt.want))
+			}
+			for i, want := range tt.want {
+				want.InstanceID, want.AccountID, want.Region = inst.ID, inst.AccountID, inst.Region
+				if got[i] != want {
+					t.Errorf("violation %d = %+v, want %+v", i, got[i], want)
+				}
+			}
+		})
+	}
+}
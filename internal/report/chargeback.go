@@ -0,0 +1,192 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Untagged is the value used when an instance lacks a chargeback tag
+const Untagged = "untagged"
+
+// ChargebackRow aggregates spend and savings for one combination of tag values.
+type ChargebackRow struct {
+	Tags            map[string]string `json:"tags"`
+	Instances       int               `json:"instances"`
+	MonthlyCost     float64           `json:"monthly_cost"`
+	EstimatedSaving float64           `json:"estimated_saving"`
+	BilledCost      *float64          `json:"billed_monthly_cost,omitempty"`
+}
+
+// Chargeback is cost allocation across the requested tag keys.
+type Chargeback struct {
+	TagKeys []string        `json:"tag_keys"`
+	Rows    []ChargebackRow `json:"rows"`
+	Total   ChargebackRow   `json:"total"`
+}
+
+// BuildChargeback groups recommendations by the values of tagKeys on each instance.
+// Instances missing a key are counted under "untagged" for that key.
+func BuildChargeback(recs []model.Recommendation, instances map[string]model.EC2Instance, tagKeys []string) Chargeback {
+	cb := Chargeback{TagKeys: tagKeys}
+	index := map[string]int{}
+
+	for _, r := range recs {
+		tags := map[string]string{}
+		for _, k := range tagKeys {
+			tags[k] = chargebackValue(instances[r.InstanceID].Tags[k])
+		}
+
+		row := cb.row(index, tags)
+		row.Instances++
+		row.MonthlyCost += r.MonthlyCost
+		row.EstimatedSaving += r.EstimatedSaving
+	}
+
+	cb.sortRows()
+	cb.total()
+	return cb
+}
+
+// Reconcile attaches billed cost from Cost Explorer's TAG group-by to each row.
+// Billed groups with no analysed instances are added as rows of their own.
+func (cb *Chargeback) Reconcile(costs []model.TagCost) {
+	index := map[string]int{}
+	for i, row := range cb.Rows {
+		index[cb.key(row.Tags)] = i
+	}
+
+	for _, c := range costs {
+		tags := map[string]string{}
+		for _, k := range cb.TagKeys {
+			tags[k] = chargebackValue(c.Tags[k])
+		}
+
+		row := cb.row(index, tags)
+		billed := c.MonthlyCost
+		if row.BilledCost != nil {
+			billed += *row.BilledCost
+		}
+		row.BilledCost = &billed
+	}
+
+	// Rows without billing data reconcile to zero
+	for i := range cb.Rows {
+		if cb.Rows[i].BilledCost == nil {
+			zero := 0.0
+			cb.Rows[i].BilledCost = &zero
+		}
+	}
+
+	cb.sortRows()
+	cb.total()
+}
+
+// row returns the row for a tag combination, creating it if needed
+func (cb *Chargeback) row(index map[string]int, tags map[string]string) *ChargebackRow {
+	key := cb.key(tags)
+	i, ok := index[key]
+	if !ok {
+		i = len(cb.Rows)
+		index[key] = i
+		cb.Rows = append(cb.Rows, ChargebackRow{Tags: tags})
+	}
+	return &cb.Rows[i]
+}
+
+func (cb *Chargeback) key(tags map[string]string) string {
+	parts := make([]string, len(cb.TagKeys))
+	for i, k := range cb.TagKeys {
+		parts[i] = tags[k]
+	}
+	return strings.Join(parts, "\x00")
+}
+
+// untagged reports whether a row has no values for any requested key
+func (cb *Chargeback) untagged(row ChargebackRow) bool {
+	for _, k := range cb.TagKeys {
+		if row.Tags[k] != Untagged {
+			return false
+		}
+	}
+	return true
+}
+
+// sortRows orders rows by cost, highest first, keeping the fully untagged bucket last
+func (cb *Chargeback) sortRows() {
+	sort.SliceStable(cb.Rows, func(i, j int) bool {
+		ui, uj := cb.untagged(cb.Rows[i]), cb.untagged(cb.Rows[j])
+		if ui != uj {
+			return uj
+		}
+		return cb.Rows[i].MonthlyCost > cb.Rows[j].MonthlyCost
+	})
+}
+
+func (cb *Chargeback) total() {
+	t := ChargebackRow{Tags: map[string]string{}}
+	var billed *float64
+	for _, row := range cb.Rows {
+		t.Instances += row.Instances
+		t.MonthlyCost += row.MonthlyCost
+		t.EstimatedSaving += row.EstimatedSaving
+		if row.BilledCost != nil {
+			if billed == nil {
+				billed = new(float64)
+			}
+			*billed += *row.BilledCost
+		}
+	}
+	t.BilledCost = billed
+	cb.Total = t
+}
+
+func chargebackValue(v string) string {
+	if v == "" {
+		return Untagged
+	}
+	return v
+}
+
+// RenderChargebackTable writes the chargeback as an aligned text table
+func RenderChargebackTable(out io.Writer, cb Chargeback) error {
+	reconciled := cb.Total.BilledCost != nil
+
+	w := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+
+	header := make([]string, 0, len(cb.TagKeys)+5)
+	for _, k := range cb.TagKeys {
+		header = append(header, strings.ToUpper(k))
+	}
+	header = append(header, "INSTANCES", "COST/mo", "SAVING")
+	if reconciled {
+		header = append(header, "BILLED/mo", "DIFF")
+	}
+	fmt.Fprintln(w, strings.Join(header, "\t"))
+
+	writeRow := func(labels []string, row ChargebackRow) {
+		cols := append(labels, fmt.Sprintf("%d", row.Instances), fmt.Sprintf("$%.2f", row.MonthlyCost), fmt.Sprintf("$%.2f", row.EstimatedSaving))
+		if reconciled && row.BilledCost != nil {
+			cols = append(cols, fmt.Sprintf("$%.2f", *row.BilledCost), fmt.Sprintf("%+.2f", *row.BilledCost-row.MonthlyCost))
+		}
+		fmt.Fprintln(w, strings.Join(cols, "\t"))
+	}
+
+	for _, row := range cb.Rows {
+		labels := make([]string, len(cb.TagKeys))
+		for i, k := range cb.TagKeys {
+			labels[i] = row.Tags[k]
+		}
+		writeRow(labels, row)
+	}
+
+	totalLabels := make([]string, len(cb.TagKeys))
+	totalLabels[0] = "TOTAL"
+	writeRow(totalLabels, cb.Total)
+
+	return w.Flush()
+}
@@ -0,0 +1,52 @@
+package report
+
+import (
+	"encoding/csv"
+	"io"
+	"strconv"
+)
+
+func init() {
+	Register("csv", csvRenderer{})
+}
+
+// csvRenderer outputs one row per recommendation with raw numeric values for spreadsheets.
+// The summary is left out so the file stays a single rectangular table.
+type csvRenderer struct{}
+
+func (csvRenderer) Render(out io.Writer, rep Report) error {
+	w := csv.NewWriter(out)
+	w.Write([]string{
+		"instance_id", "instance_type", "state", "avg_cpu", "peak_cpu", "monthly_cost",
+		"hourly_cost", "action", "suggested_type", "estimated_saving", "reason", "cpu_coverage",
+		"confidence", "confidence_level",
+	})
+	for _, r := range rep.Recommendations {
+		coverage := ""
+		if r.DataQuality != nil && r.DataQuality.ExpectedPoints > 0 {
+			coverage = formatFloat(r.DataQuality.CoveragePct)
+		}
+		w.Write([]string{
+			r.InstanceID,
+			r.InstanceType,
+			r.State,
+			formatFloat(r.AvgCPU),
+			formatFloat(r.PeakCPU),
+			formatFloat(r.MonthlyCost),
+			strconv.FormatFloat(r.HourlyCost, 'f', 4, 64),
+			r.Action,
+			r.SuggestedType,
+			formatFloat(r.EstimatedSaving),
+			r.Reason,
+			coverage,
+			formatFloat(r.Confidence),
+			r.ConfidenceLevel,
+		})
+	}
+	w.Flush()
+	return w.Error()
+}
+
+func formatFloat(v float64) string {
+	return strconv.FormatFloat(v, 'f', 2, 64)
+}
@@ -0,0 +1,179 @@
+package report
+
+import (
+	"html/template"
+	"io"
+	"time"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func init() {
+	Register("html", htmlRenderer{})
+}
+
+// htmlRenderer outputs a self-contained page with a sortable table and totals
+type htmlRenderer struct{}
+
+type htmlData struct {
+	Generated  string
+	Actions    []string
+	Breakdowns []htmlBreakdown
+	Report
+}
+
+type htmlBreakdown struct {
+	Title string
+	Rows  []model.Breakdown
+}
+
+func (htmlRenderer) Render(w io.Writer, rep Report) error {
+	data := htmlData{
+		Generated: time.Now().UTC().Format("2006-01-02 15:04 MST"),
+		Actions:   sortedActions(rep.Summary.ActionCounts),
+		Breakdowns: []htmlBreakdown{
+			{"Family", rep.Summary.ByFamily},
+			{"State", rep.Summary.ByState},
+		},
+		Report: rep,
+	}
+	return htmlTemplate.Execute(w, data)
+}
+
+var htmlTemplate = template.Must(template.New("report").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Cloud Optimiser Report</title>
+<style>
+  body { font-family: -apple-system, "Segoe UI", Roboto, sans-serif; margin: 2rem; color: #222; }
+  h1 { margin-bottom: 0.2rem; }
+  .meta { color: #666; margin-bottom: 1.5rem; }
+  table { border-collapse: collapse; width: 100%; font-size: 0.9rem; }
+  th, td { border: 1px solid #ddd; padding: 0.4rem 0.6rem; text-align: left; }
+  th { background: #f4f4f4; cursor: pointer; user-select: none; white-space: nowrap; }
+  th.asc::after { content: " \25B2"; }
+  th.desc::after { content: " \25BC"; }
+  td.num { text-align: right; white-space: nowrap; }
+  tfoot td { font-weight: bold; background: #fafafa; }
+  tbody tr:nth-child(even) { background: #fcfcfc; }
+  .cards { display: flex; flex-wrap: wrap; gap: 1rem; margin-bottom: 1.5rem; }
+  .card { border: 1px solid #ddd; border-radius: 6px; padding: 0.8rem 1.2rem; min-width: 10rem; }
+  .card .value { font-size: 1.4rem; font-weight: bold; }
+  .card .label { color: #666; font-size: 0.85rem; }
+  .breakdowns { display: flex; flex-wrap: wrap; gap: 2rem; margin-bottom: 1.5rem; }
+  .breakdowns table { width: auto; }
+  h2 { margin-top: 2rem; }
+</style>
+</head>
+<body>
+<h1>Cloud Optimiser Report</h1>
+<div class="meta">Generated {{.Generated}} &middot; {{len .Recommendations}} instance(s)</div>
+{{- with .Summary}}
+<h2>Summary</h2>
+<div class="cards">
+  <div class="card"><div class="value">{{printf "$%.2f" .TotalMonthlyCost}}</div><div class="label">Monthly spend analysed</div></div>
+  <div class="card"><div class="value">{{printf "$%.2f" .TotalEstimatedSaving}}</div><div class="label">Estimated savings / month ({{printf "%.1f%%" .SavingPercent}})</div></div>
+  <div class="card"><div class="value">{{printf "%.0f%%" .UnderutilisedPercent}}</div><div class="label">Under-utilised ({{.UnderutilisedCount}} of {{.InstancesAnalysed}})</div></div>
+</div>
+{{- end}}
+<div class="breakdowns">
+<table>
+<thead><tr><th>Action</th><th data-type="num">Count</th></tr></thead>
+<tbody>
+{{- range .Actions}}
+<tr><td>{{.}}</td><td class="num" data-value="{{index $.Summary.ActionCounts .}}">{{index $.Summary.ActionCounts .}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- if .Summary.TopSavers}}
+<table>
+<thead><tr><th>Top saver</th><th>Change</th><th data-type="num">Saving</th></tr></thead>
+<tbody>
+{{- range .Summary.TopSavers}}
+<tr><td>{{.InstanceID}}</td><td>{{.InstanceType}}{{if .SuggestedType}} &rarr; {{.SuggestedType}}{{end}} ({{.Action}})</td><td class="num" data-value="{{.EstimatedSaving}}">{{printf "$%.2f" .EstimatedSaving}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- range .Breakdowns}}
+<table>
+<thead><tr><th>{{.Title}}</th><th data-type="num">Instances</th><th data-type="num">Cost/mo</th><th data-type="num">Saving</th></tr></thead>
+<tbody>
+{{- range .Rows}}
+<tr><td>{{.Key}}</td><td class="num" data-value="{{.Instances}}">{{.Instances}}</td><td class="num" data-value="{{.MonthlyCost}}">{{printf "$%.2f" .MonthlyCost}}</td><td class="num" data-value="{{.EstimatedSaving}}">{{printf "$%.2f" .EstimatedSaving}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+{{- if .Summary.ExpiringCommitments}}
+<table>
+<thead><tr><th>Expiring commitment</th><th>Ends</th><th data-type="num">Days</th><th data-type="num">Increase/mo</th></tr></thead>
+<tbody>
+{{- range .Summary.ExpiringCommitments}}
+<tr><td>{{.Description}} ({{.ID}})</td><td>{{.End.Format "2006-01-02"}}</td><td class="num" data-value="{{.DaysLeft}}">{{.DaysLeft}}</td><td class="num" data-value="{{.MonthlyIncrease}}">{{printf "%+.2f" .MonthlyIncrease}}</td></tr>
+{{- end}}
+</tbody>
+</table>
+{{- end}}
+</div>
+<h2>Recommendations</h2>
+<table id="recs">
+<thead>
+<tr>
+  <th>ID</th><th>Type</th><th>State</th>
+  <th data-type="num">CPU (avg)</th><th data-type="num">CPU (peak)</th>
+  <th data-type="num">Cost/mo</th><th>Action</th><th>New Type</th>
+  <th data-type="num">Saving</th><th data-type="num">Confidence</th><th>Reason</th>
+</tr>
+</thead>
+<tbody>
+{{- range .Recommendations}}
+<tr>
+  <td>{{.InstanceID}}</td><td>{{.InstanceType}}</td><td>{{.State}}</td>
+  <td class="num" data-value="{{.AvgCPU}}">{{printf "%.1f%%" .AvgCPU}}</td>
+  <td class="num" data-value="{{.PeakCPU}}">{{printf "%.1f%%" .PeakCPU}}</td>
+  <td class="num" data-value="{{.MonthlyCost}}">{{printf "$%.2f" .MonthlyCost}}</td>
+  <td>{{.Action}}</td><td>{{.SuggestedType}}</td>
+  <td class="num" data-value="{{.EstimatedSaving}}">{{printf "$%.2f" .EstimatedSaving}}</td>
+  <td class="num" data-value="{{.Confidence}}">{{printf "%.2f" .Confidence}} {{.ConfidenceLevel}}</td>
+  <td>{{.Reason}}</td>
+</tr>
+{{- end}}
+</tbody>
+<tfoot>
+<tr>
+  <td colspan="5">Total</td>
+  <td class="num">{{printf "$%.2f" .Summary.TotalMonthlyCost}}</td>
+  <td colspan="2"></td>
+  <td class="num">{{printf "$%.2f" .Summary.TotalEstimatedSaving}}</td>
+  <td colspan="2"></td>
+</tr>
+</tfoot>
+</table>
+<script>
+document.querySelectorAll("table").forEach(function (table) {
+  var headers = table.querySelectorAll("thead th");
+  headers.forEach(function (th, col) {
+    th.addEventListener("click", function () {
+      var asc = !th.classList.contains("asc");
+      headers.forEach(function (h) { h.classList.remove("asc", "desc"); });
+      th.classList.add(asc ? "asc" : "desc");
+      var numeric = th.dataset.type === "num";
+      var body = table.tBodies[0];
+      var rows = Array.prototype.slice.call(body.rows);
+      rows.sort(function (a, b) {
+        var x = a.cells[col], y = b.cells[col];
+        var cmp = numeric
+          ? parseFloat(x.dataset.value) - parseFloat(y.dataset.value)
+          : x.textContent.localeCompare(y.textContent);
+        return asc ? cmp : -cmp;
+      });
+      rows.forEach(function (r) { body.appendChild(r); });
+    });
+  });
+});
+</script>
+</body>
+</html>
+`))
@@ -0,0 +1,36 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+func init() {
+	Register("json", jsonRenderer{})
+	Register("json-report", jsonReportRenderer{})
+}
+
+// jsonRenderer outputs the recommendations as an indented JSON array, the
+// shape existing consumers of --output json expect
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(w io.Writer, r Report) error {
+	return writeJSON(w, r.Recommendations)
+}
+
+// jsonReportRenderer outputs the summary and recommendations as one document
+type jsonReportRenderer struct{}
+
+func (jsonReportRenderer) Render(w io.Writer, r Report) error {
+	return writeJSON(w, r)
+}
+
+func writeJSON(w io.Writer, v any) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal JSON: %w", err)
+	}
+	_, err = fmt.Fprintln(w, string(b))
+	return err
+}
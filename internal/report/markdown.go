@@ -0,0 +1,106 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func init() {
+	Register("markdown", markdownRenderer{})
+}
+
+// markdownRenderer outputs a GitHub-flavoured table for PR and wiki comments
+type markdownRenderer struct{}
+
+func (markdownRenderer) Render(w io.Writer, rep Report) error {
+	var sb strings.Builder
+	writeMarkdownSummary(&sb, rep.Summary)
+
+	sb.WriteString("## Recommendations\n\n")
+	sb.WriteString("| ID | Type | State | CPU (avg) | CPU (peak) | Cost/mo | Action | New Type | Saving | Confidence | Reason |\n")
+	sb.WriteString("|----|------|-------|----------:|-----------:|--------:|--------|----------|-------:|-----------:|--------|\n")
+
+	for _, r := range rep.Recommendations {
+		fmt.Fprintf(&sb, "| %s | %s | %s | %.1f%% | %.1f%% | $%.2f | %s | %s | $%.2f | %.2f %s | %s |\n",
+			mdEscape(r.InstanceID),
+			mdEscape(r.InstanceType),
+			mdEscape(r.State),
+			r.AvgCPU,
+			r.PeakCPU,
+			r.MonthlyCost,
+			mdEscape(r.Action),
+			mdEscape(r.SuggestedType),
+			r.EstimatedSaving,
+			r.Confidence,
+			mdEscape(r.ConfidenceLevel),
+			mdEscape(r.Reason),
+		)
+	}
+	fmt.Fprintf(&sb, "| **Total** | | | | | **$%.2f** | | | **$%.2f** | | |\n",
+		rep.Summary.TotalMonthlyCost, rep.Summary.TotalEstimatedSaving)
+
+	_, err := io.WriteString(w, sb.String())
+	return err
+}
+
+// writeMarkdownSummary writes the executive summary section
+func writeMarkdownSummary(sb *strings.Builder, s model.Summary) {
+	sb.WriteString("## Summary\n\n")
+	fmt.Fprintf(sb, "- **Instances analysed:** %d\n", s.InstancesAnalysed)
+	fmt.Fprintf(sb, "- **Monthly spend analysed:** $%.2f\n", s.TotalMonthlyCost)
+	fmt.Fprintf(sb, "- **Estimated savings:** $%.2f/mo (%.1f%%)\n", s.TotalEstimatedSaving, s.SavingPercent)
+	fmt.Fprintf(sb, "- **Under-utilised:** %d of %d (%.0f%%)\n", s.UnderutilisedCount, s.InstancesAnalysed, s.UnderutilisedPercent)
+
+	var actions []string
+	for _, a := range sortedActions(s.ActionCounts) {
+		actions = append(actions, fmt.Sprintf("%s: %d", mdEscape(a), s.ActionCounts[a]))
+	}
+	if len(actions) > 0 {
+		fmt.Fprintf(sb, "- **Actions:** %s\n", strings.Join(actions, ", "))
+	}
+	sb.WriteString("\n")
+
+	if len(s.TopSavers) > 0 {
+		sb.WriteString("### Top savers\n\n| ID | Change | Action | Saving |\n|----|--------|--------|-------:|\n")
+		for _, sv := range s.TopSavers {
+			fmt.Fprintf(sb, "| %s | %s | %s | $%.2f |\n",
+				mdEscape(sv.InstanceID), mdEscape(typeChange(sv.InstanceType, sv.SuggestedType, "→")), mdEscape(sv.Action), sv.EstimatedSaving)
+		}
+		sb.WriteString("\n")
+	}
+
+	for _, group := range []struct {
+		title string
+		rows  []model.Breakdown
+	}{
+		{"By family", s.ByFamily},
+		{"By state", s.ByState},
+	} {
+		if len(group.rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(sb, "### %s\n\n| Group | Instances | Cost/mo | Saving |\n|-------|----------:|--------:|-------:|\n", group.title)
+		for _, b := range group.rows {
+			fmt.Fprintf(sb, "| %s | %d | $%.2f | $%.2f |\n", mdEscape(b.Key), b.Instances, b.MonthlyCost, b.EstimatedSaving)
+		}
+		sb.WriteString("\n")
+	}
+
+	if len(s.ExpiringCommitments) > 0 {
+		sb.WriteString("### Expiring commitments\n\n| ID | Commitment | Ends | Days | Increase/mo |\n|----|------------|------|-----:|------------:|\n")
+		for _, e := range s.ExpiringCommitments {
+			fmt.Fprintf(sb, "| %s | %s | %s | %d | %+.2f |\n",
+				mdEscape(e.ID), mdEscape(e.Description), e.End.Format("2006-01-02"), e.DaysLeft, e.MonthlyIncrease)
+		}
+		sb.WriteString("\n")
+	}
+}
+
+// mdEscape keeps cell content from breaking the table
+func mdEscape(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
@@ -0,0 +1,39 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Renderer writes a report in a single output format.
+type Renderer interface {
+	Render(w io.Writer, r Report) error
+}
+
+var renderers = map[string]Renderer{}
+
+// Register makes a renderer available under the given format name.
+func Register(format string, r Renderer) {
+	renderers[format] = r
+}
+
+// Get returns the renderer for a format, or an error listing valid formats.
+func Get(format string) (Renderer, error) {
+	r, ok := renderers[format]
+	if !ok {
+		return nil, fmt.Errorf("unknown output format %q (valid: %s)", format, strings.Join(Formats(), " | "))
+	}
+	return r, nil
+}
+
+// Formats returns the registered format names in sorted order.
+func Formats() []string {
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
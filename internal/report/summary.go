@@ -0,0 +1,146 @@
+package report
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+// Report is everything a renderer needs to produce output.
+type Report struct {
+	Summary         model.Summary          `json:"summary"`
+	Recommendations []model.Recommendation `json:"recommendations"`
+}
+
+// underutilisedActions are the actions that mean an instance is larger than it needs to be
+var underutilisedActions = map[string]bool{
+	"Downsize":                  true,
+	"Review / Potentially Stop": true,
+	"Stop":                      true,
+	"Terminate":                 true,
+	"Snapshot & Terminate":      true,
+}
+
+// New builds a report, summarising the recommendations with the top N savers.
+func New(recs []model.Recommendation, topN int) Report {
+	return Report{
+		Summary:         Summarise(recs, topN),
+		Recommendations: recs,
+	}
+}
+
+// Summarise computes fleet totals, action counts and breakdowns from recommendations.
+func Summarise(recs []model.Recommendation, topN int) model.Summary {
+	s := model.Summary{
+		InstancesAnalysed: len(recs),
+		ActionCounts:      map[string]int{},
+	}
+
+	families := map[string]*model.Breakdown{}
+	states := map[string]*model.Breakdown{}
+	var savers []model.Saver
+
+	for _, r := range recs {
+		s.TotalMonthlyCost += r.MonthlyCost
+		s.TotalEstimatedSaving += r.EstimatedSaving
+		s.ActionCounts[r.Action]++
+
+		if underutilisedActions[r.Action] {
+			s.UnderutilisedCount++
+		}
+
+		addTo(families, instanceFamily(r.InstanceType), r)
+		addTo(states, r.State, r)
+
+		if r.EstimatedSaving > 0 {
+			savers = append(savers, model.Saver{
+				InstanceID:      r.InstanceID,
+				InstanceType:    r.InstanceType,
+				Action:          r.Action,
+				SuggestedType:   r.SuggestedType,
+				EstimatedSaving: r.EstimatedSaving,
+			})
+		}
+	}
+
+	if s.TotalMonthlyCost > 0 {
+		s.SavingPercent = s.TotalEstimatedSaving / s.TotalMonthlyCost * 100
+	}
+	if s.InstancesAnalysed > 0 {
+		s.UnderutilisedPercent = float64(s.UnderutilisedCount) / float64(s.InstancesAnalysed) * 100
+	}
+
+	sort.SliceStable(savers, func(i, j int) bool { return savers[i].EstimatedSaving > savers[j].EstimatedSaving })
+	if topN >= 0 && len(savers) > topN {
+		savers = savers[:topN]
+	}
+	s.TopSavers = savers
+
+	s.ByFamily = sortedBreakdowns(families)
+	s.ByState = sortedBreakdowns(states)
+
+	return s
+}
+
+// instanceFamily returns the family prefix of an instance type (m5.large -> m5)
+func instanceFamily(instanceType string) string {
+	if instanceType == "" {
+		return "unknown"
+	}
+	family, _, _ := strings.Cut(instanceType, ".")
+	return family
+}
+
+func addTo(groups map[string]*model.Breakdown, key string, r model.Recommendation) {
+	if key == "" {
+		key = "unknown"
+	}
+	b, ok := groups[key]
+	if !ok {
+		b = &model.Breakdown{Key: key}
+		groups[key] = b
+	}
+	b.Instances++
+	b.MonthlyCost += r.MonthlyCost
+	b.EstimatedSaving += r.EstimatedSaving
+}
+
+// sortedBreakdowns orders groups by monthly cost, highest first
+func sortedBreakdowns(groups map[string]*model.Breakdown) []model.Breakdown {
+	out := make([]model.Breakdown, 0, len(groups))
+	for _, b := range groups {
+		out = append(out, *b)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].MonthlyCost != out[j].MonthlyCost {
+			return out[i].MonthlyCost > out[j].MonthlyCost
+		}
+		return out[i].Key < out[j].Key
+	})
+	return out
+}
+
+// sortedActions returns action names in a stable display order
+func sortedActions(counts map[string]int) []string {
+	names := make([]string, 0, len(counts))
+	for name := range counts {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if counts[names[i]] != counts[names[j]] {
+			return counts[names[i]] > counts[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	return names
+}
+
+// typeChange describes a saver's type change, or just its type when the
+// action (e.g. Stop) keeps it
+func typeChange(from, to, arrow string) string {
+	if to == "" || to == from {
+		return from
+	}
+	return from + " " + arrow + " " + to
+}
@@ -0,0 +1,113 @@
+package report
+
+import (
+	"fmt"
+	"io"
+	"text/tabwriter"
+
+	"github.com/PanaAnt/cloud-optimiser/internal/model"
+)
+
+func init() {
+	Register("table", tableRenderer{})
+}
+
+// tableRenderer outputs recommendations as an aligned text table followed by a summary
+type tableRenderer struct{}
+
+func (tableRenderer) Render(out io.Writer, rep Report) error {
+	w := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tTYPE\tSTATE\tCPU(avg)\tCPU(peak)\tCOST/mo\tACTION\tNEW TYPE\tSAVING\tCONFIDENCE\tREASON")
+	for _, r := range rep.Recommendations {
+		fmt.Fprintf(
+			w,
+			"%s\t%s\t%s\t%.1f%%\t%.1f%%\t$%.2f\t%s\t%s\t$%.2f\t%.2f %s\t%s\n",
+			r.InstanceID,
+			r.InstanceType,
+			r.State,
+			r.AvgCPU,
+			r.PeakCPU,
+			r.MonthlyCost,
+			r.Action,
+			r.SuggestedType,
+			r.EstimatedSaving,
+			r.Confidence,
+			r.ConfidenceLevel,
+			r.Reason,
+		)
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	return renderTextSummary(out, rep)
+}
+
+// renderTextSummary prints the executive summary block
+func renderTextSummary(out io.Writer, rep Report) error {
+	s := rep.Summary
+
+	fmt.Fprintln(out)
+	fmt.Fprintln(out, "SUMMARY")
+
+	w := tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+	fmt.Fprintf(w, "  Instances analysed:\t%d\n", s.InstancesAnalysed)
+	fmt.Fprintf(w, "  Monthly spend analysed:\t$%.2f\n", s.TotalMonthlyCost)
+	fmt.Fprintf(w, "  Estimated savings:\t$%.2f/mo (%.1f%%)\n", s.TotalEstimatedSaving, s.SavingPercent)
+	fmt.Fprintf(w, "  Under-utilised:\t%d of %d (%.0f%%)\n", s.UnderutilisedCount, s.InstancesAnalysed, s.UnderutilisedPercent)
+	for i, action := range sortedActions(s.ActionCounts) {
+		label := ""
+		if i == 0 {
+			label = "  Actions:"
+		}
+		fmt.Fprintf(w, "%s\t%s: %d\n", label, action, s.ActionCounts[action])
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	if len(s.TopSavers) > 0 {
+		fmt.Fprintln(out, "\n  Top savers:")
+		w = tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+		for _, sv := range s.TopSavers {
+			fmt.Fprintf(w, "    %s\t%s\t%s\t$%.2f/mo\n", sv.InstanceID, typeChange(sv.InstanceType, sv.SuggestedType, "->"), sv.Action, sv.EstimatedSaving)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	for _, group := range []struct {
+		title string
+		rows  []model.Breakdown
+	}{
+		{"By family", s.ByFamily},
+		{"By state", s.ByState},
+	} {
+		if len(group.rows) == 0 {
+			continue
+		}
+		fmt.Fprintf(out, "\n  %s:\n", group.title)
+		w = tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+		for _, row := range group.rows {
+			fmt.Fprintf(w, "    %s\t%d instance(s)\t$%.2f/mo\tsave $%.2f\n", row.Key, row.Instances, row.MonthlyCost, row.EstimatedSaving)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	if len(s.ExpiringCommitments) > 0 {
+		fmt.Fprintln(out, "\n  Expiring commitments:")
+		w = tabwriter.NewWriter(out, 2, 4, 2, ' ', 0)
+		for _, e := range s.ExpiringCommitments {
+			fmt.Fprintf(w, "    %s\t%s\tends %s (%d days)\t%+.2f/mo after expiry\n",
+				e.ID, e.Description, e.End.Format("2006-01-02"), e.DaysLeft, e.MonthlyIncrease)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}